@@ -1,17 +1,38 @@
 package gintestutil
 
 import (
-	"sync"
-	"testing"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	// defaultTimeout is the default value for EnsureCompletion's config
 	defaultTimeout = 30 * time.Second
+
+	// defaultConsistentlyTimeout is the default duration in which Consistently checks the condition
+	defaultConsistentlyTimeout = time.Second
+
+	// defaultInterval is the default interval between checks of Eventually and Consistently
+	defaultInterval = 10 * time.Millisecond
+
+	// deadlineMargin is subtracted from the deadline of the test, so a timeout is reported before go test panics
+	deadlineMargin = time.Second
+
+	// TimeoutScaleEnv is the environment variable with a factor that all timeouts are multiplied by, such as 2.5
+	// on slow CI runners
+	TimeoutScaleEnv = "GINTESTUTIL_TIMEOUT_SCALE"
 )
 
-// EnsureOption allows various options to be supplied to EnsureCompletion
+// EnsureOption allows various options to be supplied to EnsureCompletion, Eventually and Consistently
 type EnsureOption func(*ensureConfig)
 
 // WithTimeout is used to set a timeout for EnsureCompletion
@@ -21,44 +42,321 @@ func WithTimeout(timeout time.Duration) EnsureOption {
 	}
 }
 
+// WithInterval is used to set the interval between checks of Eventually and Consistently
+func WithInterval(interval time.Duration) EnsureOption {
+	return func(config *ensureConfig) {
+		config.interval = interval
+	}
+}
+
 type ensureConfig struct {
-	timeout time.Duration
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// newEnsureConfig returns the config with the given default timeout and the options applied
+func newEnsureConfig(timeout time.Duration, options []EnsureOption) *ensureConfig {
+	config := &ensureConfig{
+		timeout:  timeout,
+		interval: defaultInterval,
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	return config
 }
 
-// EnsureCompletion ensures that the waitgroup completes within a specified duration or else fails
-func EnsureCompletion(t *testing.T, wg *sync.WaitGroup, options ...EnsureOption) bool {
+// deadlineT is implemented by TestingT implementations that know when the test times out, such as *testing.T
+type deadlineT interface {
+	Deadline() (time.Time, bool)
+}
+
+// Waiter is anything that can be waited on by EnsureCompletion, such as a *sync.WaitGroup or a *CallExpectation
+type Waiter interface {
+	Wait()
+}
+
+// isNilWaiter also catches typed nil pointers, such as the nil *CallExpectation of a failed ExpectCalled
+func isNilWaiter(waiter Waiter) bool {
+	if waiter == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(waiter)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// testDeadline returns the deadline of the test, a testing.T that wasn't created by go test panics instead
+func testDeadline(t deadlineT) (time.Time, bool) {
+	// A recovered panic makes this function return no deadline
+	defer func() {
+		_ = recover()
+	}()
+
+	return t.Deadline()
+}
+
+// effectiveTimeout applies the scale factor of TimeoutScaleEnv to the timeout and caps it at the deadline of the test,
+// the returned bool is true if the deadline was used
+func effectiveTimeout(t TestingT, timeout time.Duration) (time.Duration, bool) {
 	t.Helper()
 
-	if wg == nil {
-		t.Error("WithExpectation is nil")
+	if scale := os.Getenv(TimeoutScaleEnv); scale != "" {
+		factor, err := strconv.ParseFloat(scale, 64)
+		if err != nil || factor <= 0 {
+			t.Errorf("%s must be a positive number, got '%s'", TimeoutScaleEnv, scale)
+		} else {
+			timeout = time.Duration(float64(timeout) * factor)
+		}
+	}
 
-		return false
+	withDeadline, ok := t.(deadlineT)
+	if !ok {
+		return timeout, false
 	}
 
-	config := &ensureConfig{
-		timeout: defaultTimeout,
+	deadline, ok := testDeadline(withDeadline)
+	if !ok {
+		return timeout, false
 	}
 
-	for _, option := range options {
-		option(config)
+	if remaining := time.Until(deadline) - deadlineMargin; remaining < timeout {
+		return remaining, true
+	}
+
+	return timeout, false
+}
+
+// waitChannel returns a channel that is closed when the waiter completes. Expectations that don't share an external
+// wait group are tracked without a goroutine, for other waiters the goroutine only ends when the waiter completes.
+func waitChannel(waiter Waiter) <-chan struct{} {
+	if expectation, ok := waiter.(*CallExpectation); ok {
+		if channel := expectation.group.doneChannel(); channel != nil {
+			return channel
+		}
 	}
 
-	// Run waitgroup in goroutine
 	channel := make(chan struct{})
 	go func() {
-		t.Helper()
 		defer close(channel)
-		wg.Wait()
+		waiter.Wait()
 	}()
 
-	// Select first response (waitgroup completion or time.After)
+	return channel
+}
+
+// EnsureCompletion ensures that the waiter completes within a specified duration or else fails. If the waiter is
+// a *CallExpectation, the expectations that didn't receive enough calls are reported with their observed calls.
+func EnsureCompletion(t TestingT, waiter Waiter, options ...EnsureOption) bool {
+	t.Helper()
+
+	return EnsureCompletionContext(context.Background(), t, waiter, options...)
+}
+
+// EnsureCompletionContext is EnsureCompletion that also fails when the context is done. The timeout is multiplied
+// by the factor in TimeoutScaleEnv and capped at the deadline of the test, if t has one.
+//
+// Only a *CallExpectation whose wait group is not shared with Expectation(*sync.WaitGroup) is awaited without a
+// goroutine. A sync.WaitGroup can't be waited on without blocking, so for a *sync.WaitGroup, or an expectation that
+// shares one, a goroutine stays blocked until the wait group completes, which may be never after a timeout.
+func EnsureCompletionContext(ctx context.Context, t TestingT, waiter Waiter, options ...EnsureOption) bool {
+	t.Helper()
+
+	if isNilWaiter(waiter) {
+		t.Error("WithExpectation is nil")
+
+		return false
+	}
+
+	config := newEnsureConfig(defaultTimeout, options)
+
+	timeout, capped := effectiveTimeout(t, config.timeout)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Select first response (waiter completion, context or timer)
+	var reason string
 	select {
-	case <-channel:
+	case <-waitChannel(waiter):
 		return true
 
-	case <-time.After(config.timeout):
-		t.Errorf("tasks did not complete within: %v", config.timeout)
+	case <-ctx.Done():
+		reason = "context was done before tasks completed: " + ctx.Err().Error()
+
+	case <-timer.C:
+		reason = "tasks did not complete within: " + timeout.String()
+		if capped {
+			reason += " (capped by the deadline of the test)"
+		}
+	}
+
+	expectation, ok := waiter.(*CallExpectation)
+	if !ok {
+		t.Errorf("%s", reason)
 
 		return false
 	}
+
+	t.Errorf("%s, unmet expectations:\n%s", reason, strings.Join(expectation.group.unmet(), "\n"))
+
+	return false
+}
+
+// poll checks the condition every interval until it returns the wanted result or the timeout expires, it returns
+// whether the wanted result was seen and after how long
+func poll(timeout time.Duration, interval time.Duration, condition func() bool, want bool) (bool, time.Duration) {
+	start := time.Now()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if condition() == want {
+			return true, time.Since(start)
+		}
+
+		select {
+		case <-timer.C:
+			return false, time.Since(start)
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollConfig returns the timeout and interval to poll with, the interval must be positive
+func pollConfig(t TestingT, defaultTimeout time.Duration, options []EnsureOption) (time.Duration, time.Duration, bool) {
+	t.Helper()
+
+	config := newEnsureConfig(defaultTimeout, options)
+	if config.interval <= 0 {
+		t.Errorf("interval must be positive, got %v", config.interval)
+
+		return 0, 0, false
+	}
+
+	timeout, _ := effectiveTimeout(t, config.timeout)
+
+	return timeout, config.interval, true
+}
+
+// Eventually checks the condition every interval until it returns true, or fails after the timeout. The timeout
+// defaults to 30 seconds and is scaled and capped like the timeout of EnsureCompletion.
+func Eventually(t TestingT, condition func() bool, options ...EnsureOption) bool {
+	t.Helper()
+
+	timeout, interval, ok := pollConfig(t, defaultTimeout, options)
+	if !ok {
+		return false
+	}
+
+	if ok, _ := poll(timeout, interval, condition, true); ok {
+		return true
+	}
+
+	t.Errorf("condition was not met within: %v", timeout)
+
+	return false
+}
+
+// Consistently checks the condition every interval until the timeout expires, and fails as soon as it returns false.
+// The timeout defaults to 1 second and is scaled and capped like the timeout of EnsureCompletion.
+func Consistently(t TestingT, condition func() bool, options ...EnsureOption) bool {
+	t.Helper()
+
+	timeout, interval, ok := pollConfig(t, defaultConsistentlyTimeout, options)
+	if !ok {
+		return false
+	}
+
+	failed, elapsed := poll(timeout, interval, condition, false)
+	if !failed {
+		return true
+	}
+
+	t.Errorf("condition was no longer met after: %v", elapsed.Round(time.Millisecond))
+
+	return false
+}
+
+// bodyMatches compares a response body to the expected body of EventuallyResponds
+func bodyMatches(expected any, expectedJson any, body []byte) bool {
+	switch expected := expected.(type) {
+	case nil:
+		return true
+
+	case []byte:
+		return bytes.Equal(expected, body)
+
+	case string:
+		return expected == string(body)
+	}
+
+	actual, err := decodeJsonValue(body)
+
+	return err == nil && len(diffJson("$", expectedJson, actual)) == 0
+}
+
+// EventuallyResponds sends the request made with the request options to the handler, such as a *gin.Engine, every
+// interval until it responds with the code and body or fails after the timeout. A nil body is not checked,
+// a string or []byte must be equal to the body and everything else is compared to the body as json.
+func EventuallyResponds(t TestingT, handler http.Handler, request []RequestOption, code int, body any, options ...EnsureOption) bool {
+	t.Helper()
+
+	prepared, ok := newRequest(t, newRequestConfig(request))
+	if !ok {
+		return false
+	}
+
+	var requestBody []byte
+	if prepared.Body != nil {
+		requestBody, _ = io.ReadAll(prepared.Body)
+	}
+
+	var expectedJson any
+	switch body.(type) {
+	case nil, []byte, string:
+
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Errorf("Failed to marshal expected value '%T': %v", body, err)
+
+			return false
+		}
+
+		expectedJson, _ = decodeJsonValue(data)
+	}
+
+	var recorder *httptest.ResponseRecorder
+	condition := func() bool {
+		attempt := prepared.Clone(prepared.Context())
+		attempt.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, attempt)
+
+		return recorder.Code == code && bodyMatches(body, expectedJson, recorder.Body.Bytes())
+	}
+
+	timeout, interval, ok := pollConfig(t, defaultTimeout, options)
+	if !ok {
+		return false
+	}
+
+	if ok, _ := poll(timeout, interval, condition, true); ok {
+		return true
+	}
+
+	t.Errorf("%s %s did not respond with %d and the expected body within: %v, last response: %d '%s'",
+		prepared.Method, prepared.URL, code, timeout, recorder.Code, truncateBody(recorder.Body.Bytes()))
+
+	return false
 }
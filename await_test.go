@@ -1,10 +1,15 @@
 package gintestutil
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,6 +25,20 @@ func TestEnsure_NilWaitGroupFails(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestEnsure_NilExpectationFails(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(testing.T)
+
+	var expectation *CallExpectation
+
+	// Act
+	ok := EnsureCompletion(testingObject, expectation)
+
+	// Assert
+	assert.False(t, ok)
+}
+
 func TestEnsure_NegativeDurationFails(t *testing.T) {
 	t.Parallel()
 	// Arrange
@@ -85,3 +104,315 @@ func TestEnsure_ShortTaskTimeThanEnsureDurationSucceeds(t *testing.T) {
 	assert.False(t, testingObject.Failed())
 	assert.True(t, ok)
 }
+
+func TestEnsure_ReportsUnmetExpectations(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	ginContext := gin.New()
+
+	expectation := ExpectCalled(testingObject, ginContext, http.MethodGet, "/ping", TimesCalled(2))
+	expectation = ExpectCalled(testingObject, ginContext, http.MethodPost, "/pong", Expectation(expectation), AtLeast(1))
+	expectation = ExpectCalled(testingObject, ginContext, http.MethodGet, "/done", Expectation(expectation))
+
+	ginContext.GET("/ping", func(*gin.Context) {})
+	ginContext.POST("/pong", func(*gin.Context) {})
+	ginContext.GET("/done", func(*gin.Context) {})
+
+	for _, path := range []string{"/ping", "/done"} {
+		context, _ := PrepareRequest(t, WithUrl(path))
+		ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+	}
+
+	// Act
+	ok := EnsureCompletion(testingObject, expectation, WithTimeout(100*time.Millisecond))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"tasks did not complete within: 100ms, unmet expectations:\n" +
+			"GET /ping: expected 2 times, called 1 times\n" +
+			"POST /pong: expected at least 1 times, called 0 times",
+	}, testingObject.ErrorfCalls)
+}
+
+// deadlineMockT is a mockT with a test deadline
+type deadlineMockT struct {
+	*mockT
+	deadline time.Time
+}
+
+// Deadline returns the configured deadline
+func (d *deadlineMockT) Deadline() (time.Time, bool) {
+	return d.deadline, true
+}
+
+func TestEnsureCompletionContext_FailsOnDoneContext(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+
+	expectation := &sync.WaitGroup{}
+	expectation.Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	ok := EnsureCompletionContext(ctx, testingObject, expectation)
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{"context was done before tasks completed: context canceled"}, testingObject.ErrorfCalls)
+}
+
+func TestEnsureCompletionContext_CapsTimeoutAtDeadline(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := &deadlineMockT{mockT: new(mockT), deadline: time.Now().Add(deadlineMargin + 100*time.Millisecond)}
+
+	expectation := &sync.WaitGroup{}
+	expectation.Add(1)
+
+	// Act
+	start := time.Now()
+	ok := EnsureCompletionContext(context.Background(), testingObject, expectation)
+
+	// Assert
+	assert.False(t, ok)
+	assert.Less(t, time.Since(start), time.Second)
+	if assert.Len(t, testingObject.ErrorfCalls, 1) {
+		assert.Contains(t, testingObject.ErrorfCalls[0], "(capped by the deadline of the test)")
+	}
+}
+
+func TestEnsureCompletionContext_CompletesExpectationWithoutGoroutine(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	ginContext := gin.New()
+	ginContext.GET("/ping", func(*gin.Context) {})
+
+	expectation := ExpectCalled(testingObject, ginContext, http.MethodGet, "/ping")
+	done := expectation.group.doneChannel()
+
+	// Act
+	requestContext, _ := PrepareRequest(t, WithUrl("/ping"))
+	ginContext.ServeHTTP(httptest.NewRecorder(), requestContext.Request)
+
+	// Assert
+	assert.Equal(t, done, waitChannel(expectation))
+	assert.True(t, EnsureCompletion(testingObject, expectation, WithTimeout(expectTimeout)))
+	assert.Empty(t, testingObject.ErrorfCalls)
+}
+
+//nolint:paralleltest // Environment variables can't be set in parallel tests
+func TestEnsureCompletion_ScalesTimeout(t *testing.T) {
+	tests := map[string]struct {
+		scale string
+
+		expectedErrorf []string
+	}{
+		"scaled": {
+			scale:          "0.5",
+			expectedErrorf: []string{"tasks did not complete within: 50ms"},
+		},
+		"invalid": {
+			scale: "fast",
+			expectedErrorf: []string{
+				"GINTESTUTIL_TIMEOUT_SCALE must be a positive number, got 'fast'",
+				"tasks did not complete within: 100ms",
+			},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(TimeoutScaleEnv, testData.scale)
+			testingObject := new(mockT)
+
+			expectation := &sync.WaitGroup{}
+			expectation.Add(1)
+
+			// Act
+			ok := EnsureCompletion(testingObject, expectation, WithTimeout(100*time.Millisecond))
+
+			// Assert
+			assert.False(t, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestEventually_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		delay time.Duration
+
+		expected       bool
+		expectedErrorf []string
+	}{
+		"met in time": {
+			delay:    20 * time.Millisecond,
+			expected: true,
+		},
+		"not met in time": {
+			delay:          time.Second,
+			expectedErrorf: []string{"condition was not met within: 100ms"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+
+			var done int32
+			time.AfterFunc(testData.delay, func() { atomic.StoreInt32(&done, 1) })
+
+			// Act
+			ok := Eventually(testingObject, func() bool { return atomic.LoadInt32(&done) == 1 }, WithTimeout(100*time.Millisecond), WithInterval(5*time.Millisecond))
+
+			// Assert
+			assert.Equal(t, testData.expected, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestConsistently_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		delay time.Duration
+
+		expected bool
+	}{
+		"holds": {
+			delay:    time.Second,
+			expected: true,
+		},
+		"stops holding": {
+			delay: 20 * time.Millisecond,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+
+			var broken int32
+			time.AfterFunc(testData.delay, func() { atomic.StoreInt32(&broken, 1) })
+
+			// Act
+			ok := Consistently(testingObject, func() bool { return atomic.LoadInt32(&broken) == 0 }, WithTimeout(100*time.Millisecond), WithInterval(5*time.Millisecond))
+
+			// Assert
+			assert.Equal(t, testData.expected, ok)
+			assert.Equal(t, !testData.expected, len(testingObject.ErrorfCalls) == 1)
+		})
+	}
+}
+
+func TestEventually_FailsOnInvalidInterval(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+
+	// Act
+	ok := Eventually(testingObject, func() bool { return true }, WithInterval(0))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{"interval must be positive, got 0s"}, testingObject.ErrorfCalls)
+}
+
+func TestEventuallyResponds_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		code int
+		body any
+
+		expected       bool
+		expectedErrorf []string
+	}{
+		"status only": {
+			code:     http.StatusOK,
+			expected: true,
+		},
+		"json body": {
+			code:     http.StatusOK,
+			body:     map[string]any{"name": "John", "status": "done"},
+			expected: true,
+		},
+		"raw body": {
+			code:     http.StatusOK,
+			body:     `{"name":"John","status":"done"}`,
+			expected: true,
+		},
+		"wrong body": {
+			code: http.StatusOK,
+			body: testStruct{Name: "Jane"},
+			expectedErrorf: []string{
+				`POST https://example.com/jobs did not respond with 200 and the expected body within: 200ms, last response: 200 '{"name":"John","status":"done"}'`,
+			},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+
+			var done int32
+			time.AfterFunc(20*time.Millisecond, func() { atomic.StoreInt32(&done, 1) })
+
+			ginContext := gin.New()
+			ginContext.POST("/jobs", func(ctx *gin.Context) {
+				var job testStruct
+				if err := ctx.ShouldBindJSON(&job); err != nil || atomic.LoadInt32(&done) == 0 {
+					ctx.Status(http.StatusAccepted)
+
+					return
+				}
+
+				ctx.JSON(http.StatusOK, map[string]any{"name": job.Name, "status": "done"})
+			})
+
+			request := []RequestOption{WithMethod(http.MethodPost), WithUrl("https://example.com/jobs"), WithJsonBody(t, testStruct{Name: "John"})}
+
+			// Act
+			ok := EventuallyResponds(testingObject, ginContext, request, testData.code, testData.body, WithTimeout(200*time.Millisecond))
+
+			// Assert
+			assert.Equal(t, testData.expected, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestEventuallyResponds_FailsImmediatelyOnInvalidRequest(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+
+	ginContext := gin.New()
+	request := []RequestOption{WithRoute("/jobs/:id", nil)}
+
+	// Act
+	start := time.Now()
+	ok := EventuallyResponds(testingObject, ginContext, request, http.StatusOK, nil, WithTimeout(time.Second))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{"route /jobs/:id is missing a value for parameter 'id'"}, testingObject.ErrorfCalls)
+}
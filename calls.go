@@ -0,0 +1,185 @@
+package gintestutil
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Call is a request that matched an expectation, together with the response that was sent
+type Call struct {
+	Method   string
+	Url      *url.URL
+	Header   http.Header
+	Body     []byte
+	Params   gin.Params
+	Status   int
+	Duration time.Duration
+
+	// Sequence is the order in which the call was received, across all expectations
+	Sequence uint64
+}
+
+// callSequence numbers the calls of all expectations, which allows InOrder to compare them
+var callSequence uint64
+
+// Decode decodes the body of the call into result using the decoder of the request's Content-Type,
+// bodies without a Content-Type are decoded as json
+func (c Call) Decode(t TestingT, result any) bool {
+	t.Helper()
+
+	mediaType := parseMediaType(c.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = binding.MIMEJSON
+	}
+
+	decoder, ok := decoders.get(mediaType)
+	if !ok {
+		t.Errorf("No decoder registered for Content-Type %s", mediaType)
+
+		return false
+	}
+
+	if err := decoder(c.Body, result); err != nil {
+		t.Errorf("Failed to unmarshall '%s' into '%T': %v", truncateBody(c.Body), result, err)
+
+		return false
+	}
+
+	return true
+}
+
+// expectationGroup contains the expectations that share a wait group through the Expectation option. It counts
+// the calls that are still expected itself, so completion can be awaited through a channel instead of a goroutine.
+type expectationGroup struct {
+	mutex   sync.Mutex
+	members []*CallExpectation
+
+	// external is set when the wait group was supplied by the user, which means it can't be tracked by the group
+	external bool
+
+	remaining int
+	done      chan struct{}
+}
+
+// add adds an expectation to the group
+func (g *expectationGroup) add(expectation *CallExpectation) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.members = append(g.members, expectation)
+
+	// A group that was already done can be waited on again, just like a wait group
+	wasDone := g.done != nil && g.remaining == 0
+	if g.done == nil || (wasDone && expectation.min > 0) {
+		g.done = make(chan struct{})
+	}
+
+	g.remaining += expectation.min
+	if g.remaining == 0 && !wasDone {
+		close(g.done)
+	}
+}
+
+// satisfy is called when an expectation of the group received one of its minimum calls
+func (g *expectationGroup) satisfy() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.remaining--
+	if g.remaining == 0 {
+		close(g.done)
+	}
+}
+
+// doneChannel returns a channel that is closed when all expectations of the group received their minimum calls,
+// it's nil for groups that share an external wait group
+func (g *expectationGroup) doneChannel() <-chan struct{} {
+	if g.external {
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.done
+}
+
+// unmet describes the expectations of the group that didn't receive their minimum amount of calls yet
+func (g *expectationGroup) unmet() []string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var result []string
+	for _, expectation := range g.members {
+		called := len(expectation.Calls())
+		if called >= expectation.min {
+			continue
+		}
+
+		description := fmt.Sprintf("%s: expected %s, called %d times", expectation, expectation.expected, called)
+
+		// A typo in the route is a common reason for an expectation that is never met
+		if !routeRegistered(expectation.engine, expectation.method, expectation.path) {
+			description += fmt.Sprintf(", the route is not registered, registered routes: %s", registeredRoutes(expectation.engine))
+		}
+
+		result = append(result, description)
+	}
+
+	return result
+}
+
+// CallExpectation is returned by ExpectCalled, it can be waited on with EnsureCompletion and records
+// every call that matched the expectation
+type CallExpectation struct {
+	waitGroup *sync.WaitGroup
+	group     *expectationGroup
+	engine    *gin.Engine
+	method    string
+	path      string
+
+	// min and expected are the minimum amount of calls and a description of the expected amount
+	min      int
+	expected string
+
+	mutex sync.Mutex
+	calls []Call
+}
+
+// Wait blocks until the expectation, and those that share its wait group, received their minimum amount of calls
+func (e *CallExpectation) Wait() {
+	e.waitGroup.Wait()
+}
+
+// satisfy marks one of the minimum calls of the expectation as received
+func (e *CallExpectation) satisfy() {
+	e.group.satisfy()
+	e.waitGroup.Done()
+}
+
+// Calls returns the calls that matched the expectation so far, in the order in which they completed
+func (e *CallExpectation) Calls() []Call {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return append([]Call(nil), e.calls...)
+}
+
+// String returns the method and route of the expectation
+func (e *CallExpectation) String() string {
+	return fmt.Sprintf("%s %s", e.method, e.path)
+}
+
+// record stores a call that matched the expectation
+func (e *CallExpectation) record(call Call) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.calls = append(e.calls, call)
+}
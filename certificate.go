@@ -0,0 +1,61 @@
+package gintestutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+)
+
+// SelfSignedCertificate creates a self-signed client certificate in memory with the common name, which can be
+// used with WithTLS. The certificate is valid from an hour ago until a day from now.
+func SelfSignedCertificate(t TestingT, commonName string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("failed to generate key: %v", err)
+
+		return nil
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		t.Errorf("failed to generate serial number: %v", err)
+
+		return nil
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+
+		// The certificate is its own issuer, so it can be added to the client CAs that are trusted by a server
+		IsCA: true,
+	}
+
+	data, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Errorf("failed to create certificate: %v", err)
+
+		return nil
+	}
+
+	certificate, err := x509.ParseCertificate(data)
+	if err != nil {
+		t.Errorf("failed to parse certificate: %v", err)
+
+		return nil
+	}
+
+	return certificate
+}
@@ -0,0 +1,28 @@
+package gintestutil
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfSignedCertificate_CreatesClientCertificate(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	certificate := SelfSignedCertificate(testObject, "client.example.com")
+
+	// Assert
+	assert.Empty(t, testObject.ErrorfCalls)
+	if assert.NotNil(t, certificate) {
+		assert.Equal(t, "client.example.com", certificate.Subject.CommonName)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, certificate.ExtKeyUsage)
+		assert.True(t, certificate.NotBefore.Before(time.Now()))
+		assert.True(t, certificate.NotAfter.After(time.Now()))
+		assert.NoError(t, certificate.CheckSignatureFrom(certificate))
+	}
+}
@@ -2,14 +2,27 @@ package gintestutil
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
 )
 
 // RequestOption are functions used in PrepareRequest to configure a request using the Functional Option pattern.
@@ -19,10 +32,32 @@ type RequestOption func(*requestConfig)
 type requestConfig struct {
 	method      string
 	url         string
-	body        io.ReadCloser
+	body        []byte
 	urlParams   map[string]any
 	queryParams map[string]any
 	headers     http.Header
+	route       string
+	routeParams map[string]any
+
+	// contentType is the media type of the body, set by body options
+	contentType string
+
+	// noContentHeaders disables the Content-Type and Content-Length headers derived from the body
+	noContentHeaders bool
+
+	// keys, errors, handler and fullPath seed the state that middleware would normally leave in the context
+	keys     map[string]any
+	errors   []error
+	handler  gin.HandlerFunc
+	fullPath string
+
+	// contexts derive the context of the request from its current context, in the order of the options
+	contexts []func(context.Context) (context.Context, context.CancelFunc)
+
+	// remoteAddr, forwardedFor and tls describe the client of the request
+	remoteAddr   string
+	forwardedFor []string
+	tls          *tls.ConnectionState
 }
 
 // applyQueryParams turn a map of string/[]string/maps into query parameter names as expected from the user. Check
@@ -52,11 +87,158 @@ func applyQueryParams(params map[string]any, query url.Values, keyPrefix string)
 	}
 }
 
-// PrepareRequest Formulate a request with optional properties. This returns a *gin.Context which can be used
-// in controller unit-tests. Use the returned *httptest.ResponseRecorder to perform assertions on the response.
-func PrepareRequest(t TestingT, options ...RequestOption) (*gin.Context, *httptest.ResponseRecorder) {
+// requestHeaders merges the headers of the body into the headers given by the user, where the latter take precedence.
+func requestHeaders(config *requestConfig) http.Header {
+	headers := config.headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	if config.body == nil || config.noContentHeaders {
+		return headers
+	}
+
+	if config.contentType != "" && headers.Get("Content-Type") == "" {
+		headers.Set("Content-Type", config.contentType)
+	}
+
+	if headers.Get("Content-Length") == "" {
+		headers.Set("Content-Length", strconv.Itoa(len(config.body)))
+	}
+
+	return headers
+}
+
+// clientHeaders adds the headers of the client options to the headers given by the user, where the latter take precedence
+func clientHeaders(config *requestConfig, headers http.Header) {
+	if len(config.forwardedFor) > 0 && headers.Get("X-Forwarded-For") == "" {
+		headers.Set("X-Forwarded-For", strings.Join(config.forwardedFor, ", "))
+	}
+}
+
+// renderRoute fills in the :param and *catch-all segments of a gin route template with the given parameters, every
+// parameter that is missing from the map or not part of the template is reported.
+func renderRoute(t TestingT, route string, params map[string]any) (string, bool) {
 	t.Helper()
 
+	ok := true
+	used := map[string]bool{}
+
+	segments := strings.Split(route, "/")
+	for index, segment := range segments {
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+
+		name := segment[1:]
+		used[name] = true
+
+		var value string
+		switch resultValue := params[name].(type) {
+		case string:
+			value = resultValue
+
+		case fmt.Stringer:
+			value = resultValue.String()
+
+		case nil:
+			t.Errorf("route %s is missing a value for parameter '%s'", route, name)
+			ok = false
+
+			continue
+
+		default:
+			t.Errorf("route parameter '%s' has unsupported type %T", name, resultValue)
+			ok = false
+
+			continue
+		}
+
+		if segment[0] == ':' {
+			segments[index] = url.PathEscape(value)
+
+			continue
+		}
+
+		// Catch-all values always start with a slash in gin, so we don't want to end up with two of them
+		parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for partIndex, part := range parts {
+			parts[partIndex] = url.PathEscape(part)
+		}
+
+		segments[index] = strings.Join(parts, "/")
+	}
+
+	for name := range params {
+		if !used[name] {
+			t.Errorf("route parameter '%s' is not part of route %s", name, route)
+			ok = false
+		}
+	}
+
+	return strings.Join(segments, "/"), ok
+}
+
+// registerRoute adds the handlers for the route to the engine, gin panics on invalid routes but we'd rather
+// report that as a test failure
+func registerRoute(t TestingT, engine *gin.Engine, method string, route string, handlers ...gin.HandlerFunc) bool {
+	t.Helper()
+
+	// A recovered panic makes this function return false
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			t.Errorf("failed to register route %s: %v", route, recovered)
+		}
+	}()
+
+	engine.Handle(method, route, handlers...)
+
+	return true
+}
+
+// routeContext registers the route on the engine and lets gin's router resolve the request, this way the
+// params and full path of the returned context are set exactly like they would be in production. The handler
+// becomes the last handler of the context, but it isn't run.
+func routeContext(t TestingT, engine *gin.Engine, request *http.Request, writer http.ResponseWriter, route string, handler gin.HandlerFunc) (*gin.Context, bool) {
+	t.Helper()
+
+	if handler == nil {
+		handler = func(*gin.Context) {}
+	}
+
+	// Routing runs the handlers, the guard stops the chain while routing but lets it continue afterwards
+	routing := true
+	guard := func(c *gin.Context) {
+		if routing {
+			c.Abort()
+		}
+	}
+
+	defer func() {
+		routing = false
+	}()
+
+	if !registerRoute(t, engine, request.Method, route, guard, handler) {
+		return nil, false
+	}
+
+	// Routing writes the status header, so we route using a throwaway recorder and swap in the real one afterwards
+	context := gin.CreateTestContextOnly(httptest.NewRecorder(), engine)
+	context.Request = request
+	engine.HandleContext(context)
+	context.Writer = gin.CreateTestContextOnly(writer, engine).Writer
+
+	if context.FullPath() != route {
+		t.Errorf("url %s does not match route %s", request.URL, route)
+
+		return nil, false
+	}
+
+	return context, true
+}
+
+// newRequestConfig returns the config with the defaults of PrepareRequest and the options applied
+func newRequestConfig(options []RequestOption) *requestConfig {
 	config := &requestConfig{
 		method: http.MethodGet,
 		url:    "https://example.com",
@@ -66,21 +248,123 @@ func PrepareRequest(t TestingT, options ...RequestOption) (*gin.Context, *httpte
 		option(config)
 	}
 
-	writer := httptest.NewRecorder()
-	context, _ := gin.CreateTestContext(writer)
+	return config
+}
+
+// newRequest creates the request described by the config, the path of the url is rendered from the route if there
+// is one. The request is returned even if the route could not be rendered, but nil if it could not be created at all.
+func newRequest(t TestingT, config *requestConfig) (*http.Request, bool) {
+	t.Helper()
 
-	var err error
-	if context.Request, err = http.NewRequest(config.method, config.url, config.body); err != nil {
+	// Using a bytes.Reader makes http.NewRequest set the ContentLength of the request
+	var body io.Reader
+	if config.body != nil {
+		body = bytes.NewReader(config.body)
+	}
+
+	request, err := http.NewRequest(config.method, config.url, body)
+	if err != nil {
 		t.Error(err)
 
-		return context, writer
+		return nil, false
+	}
+
+	request.Header = requestHeaders(config)
+	clientHeaders(config, request.Header)
+	request.TLS = config.tls
+
+	if config.remoteAddr != "" {
+		request.RemoteAddr = config.remoteAddr
 	}
 
-	context.Request.Header = config.headers
+	request, ok := requestContext(t, request, config)
+	if !ok {
+		return request, false
+	}
 
-	query := context.Request.URL.Query()
+	query := request.URL.Query()
 	applyQueryParams(config.queryParams, query, "")
-	context.Request.URL.RawQuery = query.Encode()
+	request.URL.RawQuery = query.Encode()
+
+	if config.route == "" {
+		return request, true
+	}
+
+	path, ok := renderRoute(t, config.route, config.routeParams)
+	if !ok {
+		return request, false
+	}
+
+	rendered, err := url.Parse(path)
+	if err != nil {
+		t.Error(err)
+
+		return request, false
+	}
+
+	request.URL.Path = rendered.Path
+	request.URL.RawPath = rendered.RawPath
+
+	return request, true
+}
+
+// PrepareRequest Formulate a request with optional properties. This returns a *gin.Context which can be used
+// in controller unit-tests. Use the returned *httptest.ResponseRecorder to perform assertions on the response.
+func PrepareRequest(t TestingT, options ...RequestOption) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	config := newRequestConfig(options)
+
+	writer := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(writer)
+
+	var ok bool
+	if context.Request, ok = newRequest(t, config); !ok {
+		return context, writer
+	}
+
+	switch {
+	case config.fullPath != "":
+		// The params of WithRoute are kept, they are resolved on a separate engine since the routes might conflict
+		var params gin.Params
+		if config.route != "" {
+			routed, ok := routeContext(t, gin.New(), context.Request, writer, config.route, nil)
+			if !ok {
+				return context, writer
+			}
+
+			params = routed.Params
+		}
+
+		// The full path can only be set by routing, so a request for the path is routed and replaced afterwards
+		probe := context.Request.Clone(context.Request.Context())
+		probe.URL.Path = probePath(config.fullPath)
+		probe.URL.RawPath = ""
+
+		routed, ok := routeContext(t, engine, probe, writer, config.fullPath, config.handler)
+		if !ok {
+			return context, writer
+		}
+
+		routed.Request = context.Request
+		routed.Params = params
+		context = routed
+
+	case config.route != "" || config.handler != nil:
+		route := config.route
+		if route == "" {
+			// Gin redirects an empty path to /, like Serve the request is sent to the root path instead
+			context.Request.URL.Path = rootPath(context.Request.URL.Path)
+			route = context.Request.URL.Path
+		}
+
+		routed, ok := routeContext(t, engine, context.Request, writer, route, config.handler)
+		if !ok {
+			return context, writer
+		}
+
+		context = routed
+	}
 
 	for key, value := range config.urlParams {
 		switch resultValue := value.(type) {
@@ -97,6 +381,105 @@ func PrepareRequest(t TestingT, options ...RequestOption) (*gin.Context, *httpte
 		}
 	}
 
+	seedContext(t, context, config)
+
+	return context, writer
+}
+
+// seedContext sets the keys and errors of WithContextKeys and WithContextErrors on the context
+func seedContext(t TestingT, context *gin.Context, config *requestConfig) {
+	t.Helper()
+
+	for key, value := range config.keys {
+		context.Set(key, value)
+	}
+
+	for _, err := range config.errors {
+		if err == nil {
+			t.Error("context errors cannot be nil")
+
+			continue
+		}
+
+		_ = context.Error(err)
+	}
+}
+
+// requestContext applies the contexts of the config to the request, the contexts are cancelled when the test finishes
+// if t has a Cleanup method
+func requestContext(t TestingT, request *http.Request, config *requestConfig) (*http.Request, bool) {
+	t.Helper()
+
+	for _, derive := range config.contexts {
+		ctx, cancel := derive(request.Context())
+		if ctx == nil {
+			t.Error("context cannot be nil")
+
+			return request, false
+		}
+
+		if cleanup, ok := t.(cleanupT); ok && cancel != nil {
+			cleanup.Cleanup(cancel)
+		}
+
+		request = request.WithContext(ctx)
+	}
+
+	return request, true
+}
+
+// rootPath returns the path, or the root path if it's empty like in the default https://example.com
+func rootPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+// probePath turns a route into a path that matches it, by using the names of the parameters as their values
+func probePath(route string) string {
+	segments := strings.Split(route, "/")
+	for index, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[index] = segment[1:]
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Serve sends the request made with the request options through the engine, so its middleware and handlers run
+// like they do in production. The returned *gin.Context is the context the handlers ran with, so its keys, errors and
+// params can be inspected, the *httptest.ResponseRecorder contains the response. WithUrlParams has no effect here,
+// since params are set by the router of the engine.
+func Serve(t TestingT, engine *gin.Engine, options ...RequestOption) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	writer := httptest.NewRecorder()
+
+	if engine == nil {
+		t.Error("engine cannot be nil")
+
+		return nil, writer
+	}
+
+	// gin.Engine.ServeHTTP returns its context to a pool, so we handle the request with our own context instead
+	context := gin.CreateTestContextOnly(writer, engine)
+
+	var ok bool
+	if context.Request, ok = newRequest(t, newRequestConfig(options)); !ok {
+		return context, writer
+	}
+
+	// Servers receive the root path for urls without a path, such as the default https://example.com
+	context.Request.URL.Path = rootPath(context.Request.URL.Path)
+
+	engine.HandleContext(context)
+
+	// Like ServeHTTP, we make sure that the status is written if the handlers didn't write anything
+	context.Writer.WriteHeaderNow()
+
 	return context, writer
 }
 
@@ -107,7 +490,8 @@ func WithMethod(method string) RequestOption {
 	}
 }
 
-// WithHeaders specifies the headers of the request
+// WithHeaders specifies the headers of the request, these take precedence over the Content-Type and Content-Length
+// headers that are set by body options
 func WithHeaders(headers http.Header) RequestOption {
 	return func(config *requestConfig) {
 		config.headers = headers
@@ -121,22 +505,116 @@ func WithUrl(reqUrl string) RequestOption {
 	}
 }
 
-// WithJsonBody specifies the request body using json.Marshal, will report an error on marshal failure
-func WithJsonBody(t TestingT, object any) RequestOption {
-	data, err := json.Marshal(object)
+// WithRoute specifies a gin route template such as /users/:id/files/*path, the path of the url is rendered
+// from the template and the given parameters. The resulting context has its params and full path set by gin's router,
+// so c.Param and c.FullPath behave like they do in production. The values can be either:
+// - string
+// - fmt.Stringer (anything with a String() method)
+func WithRoute(route string, parameters map[string]any) RequestOption {
+	return func(config *requestConfig) {
+		config.route = route
+		config.routeParams = parameters
+	}
+}
+
+// withEncodedBody is used by the body options of the various formats to report marshal errors
+// and set the body with its content type
+func withEncodedBody(t TestingT, contentType string, data []byte, err error) RequestOption {
+	t.Helper()
+
 	if err != nil {
 		t.Error(err)
 	}
 
 	return func(config *requestConfig) {
-		config.body = io.NopCloser(bytes.NewBuffer(data))
+		config.body = data
+		config.contentType = contentType
 	}
 }
 
-// WithBody allows you to define a custom body for the request
+// WithJsonBody specifies the request body using json.Marshal and sets the matching Content-Type, will report an error
+// on marshal failure
+func WithJsonBody(t TestingT, object any) RequestOption {
+	t.Helper()
+
+	data, err := json.Marshal(object)
+
+	return withEncodedBody(t, binding.MIMEJSON, data, err)
+}
+
+// WithXmlBody specifies the request body using xml.Marshal and sets the matching Content-Type, will report an error
+// on marshal failure
+func WithXmlBody(t TestingT, object any) RequestOption {
+	t.Helper()
+
+	data, err := xml.Marshal(object)
+
+	return withEncodedBody(t, binding.MIMEXML, data, err)
+}
+
+// WithYamlBody specifies the request body using yaml.Marshal and sets the matching Content-Type, will report an error
+// on marshal failure
+func WithYamlBody(t TestingT, object any) RequestOption {
+	t.Helper()
+
+	data, err := yaml.Marshal(object)
+
+	return withEncodedBody(t, binding.MIMEYAML, data, err)
+}
+
+// WithTomlBody specifies the request body using toml.Marshal and sets the matching Content-Type, will report an error
+// on marshal failure
+func WithTomlBody(t TestingT, object any) RequestOption {
+	t.Helper()
+
+	data, err := toml.Marshal(object)
+
+	return withEncodedBody(t, binding.MIMETOML, data, err)
+}
+
+// WithMsgPackBody specifies the request body using the msgpack codec that gin uses and sets the matching Content-Type,
+// will report an error on marshal failure
+func WithMsgPackBody(t TestingT, object any) RequestOption {
+	t.Helper()
+
+	var data []byte
+	err := codec.NewEncoderBytes(&data, new(codec.MsgpackHandle)).Encode(object)
+
+	return withEncodedBody(t, binding.MIMEMSGPACK, data, err)
+}
+
+// WithProtoBody specifies the request body using proto.Marshal and sets the matching Content-Type, will report an error
+// on marshal failure
+func WithProtoBody(t TestingT, message proto.Message) RequestOption {
+	t.Helper()
+
+	data, err := proto.Marshal(message)
+
+	return withEncodedBody(t, binding.MIMEPROTOBUF, data, err)
+}
+
+// WithBody allows you to define a custom body for the request, no Content-Type is set
 func WithBody(data []byte) RequestOption {
 	return func(config *requestConfig) {
-		config.body = io.NopCloser(bytes.NewBuffer(data))
+		config.body = data
+		config.contentType = ""
+	}
+}
+
+// WithoutContentHeaders prevents body options from setting the Content-Type and Content-Length headers, useful to
+// test how a handler deals with requests that lack them. Headers given using WithHeaders are still used.
+func WithoutContentHeaders() RequestOption {
+	return func(config *requestConfig) {
+		config.noContentHeaders = true
+	}
+}
+
+// WithFormBody specifies an url-encoded form as the request body and sets the matching Content-Type, unless
+// one was given using WithHeaders. Keep in mind that gin only parses form bodies of POST, PUT and PATCH requests.
+func WithFormBody(values url.Values) RequestOption {
+	return func(config *requestConfig) {
+		config.body = []byte(values.Encode())
+		config.contentType = binding.MIMEPOSTForm
 	}
 }
 
@@ -150,6 +628,103 @@ func WithUrlParams(parameters map[string]any) RequestOption {
 	}
 }
 
+// WithContextKeys sets keys on the context like c.Set does, to simulate values that are set by middleware
+// such as an authenticated user. This applies to PrepareRequest and RunMiddleware.
+func WithContextKeys(keys map[string]any) RequestOption {
+	return func(config *requestConfig) {
+		config.keys = keys
+	}
+}
+
+// WithContextErrors adds errors to the context like c.Error does, to simulate errors that were added by
+// earlier handlers. This applies to PrepareRequest and RunMiddleware.
+func WithContextErrors(errors ...error) RequestOption {
+	return func(config *requestConfig) {
+		config.errors = append(config.errors, errors...)
+	}
+}
+
+// WithHandlerName makes the handler the last handler of the context, so c.HandlerName returns its name.
+// The handler is not run by PrepareRequest.
+func WithHandlerName(handler gin.HandlerFunc) RequestOption {
+	return func(config *requestConfig) {
+		config.handler = handler
+	}
+}
+
+// WithFullPath sets the route that c.FullPath returns, such as /users/:id, without changing the url or params of
+// the request. This takes precedence over the full path of WithRoute.
+func WithFullPath(route string) RequestOption {
+	return func(config *requestConfig) {
+		config.fullPath = route
+	}
+}
+
+// WithContext sets the context of the request, to pass values such as loggers or trace spans to the handlers
+func WithContext(ctx context.Context) RequestOption {
+	return func(config *requestConfig) {
+		config.contexts = append(config.contexts, func(context.Context) (context.Context, context.CancelFunc) {
+			return ctx, nil
+		})
+	}
+}
+
+// WithDeadline sets a deadline on the context of the request, this keeps the values of WithContext if it's
+// given first
+func WithDeadline(deadline time.Time) RequestOption {
+	return func(config *requestConfig) {
+		config.contexts = append(config.contexts, func(parent context.Context) (context.Context, context.CancelFunc) {
+			return context.WithDeadline(parent, deadline)
+		})
+	}
+}
+
+// WithCancelledContext cancels the context of the request before it's handled, to simulate a client that went
+// away. This keeps the values of WithContext if it's given first.
+func WithCancelledContext() RequestOption {
+	return func(config *requestConfig) {
+		config.contexts = append(config.contexts, func(parent context.Context) (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(parent)
+			cancel()
+
+			return ctx, nil
+		})
+	}
+}
+
+// WithRemoteAddr sets the address of the client, which is used by c.RemoteIP and c.ClientIP. The address can be
+// given with or without a port, such as 192.168.1.1 or [::1]:8080.
+func WithRemoteAddr(addr string) RequestOption {
+	return func(config *requestConfig) {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(strings.Trim(addr, "[]"), "0")
+		}
+
+		config.remoteAddr = addr
+	}
+}
+
+// WithForwardedFor sets the X-Forwarded-For header to the addresses of the client and the proxies, unless the header
+// was given using WithHeaders. Gin only uses the header in c.ClientIP if the remote address is a trusted proxy,
+// so use it in combination with WithRemoteAddr. Through Serve, the trusted proxies of the engine are honoured.
+func WithForwardedFor(addresses ...string) RequestOption {
+	return func(config *requestConfig) {
+		config.forwardedFor = addresses
+	}
+}
+
+// WithTLS makes the request look like it was received over TLS, the certificates are the peer certificates of
+// the client like with mutual TLS. SelfSignedCertificate can be used to create them.
+func WithTLS(certificates ...*x509.Certificate) RequestOption {
+	return func(config *requestConfig) {
+		config.tls = &tls.ConnectionState{
+			Version:           tls.VersionTLS13,
+			HandshakeComplete: true,
+			PeerCertificates:  certificates,
+		}
+	}
+}
+
 // WithQueryParams adds query parameters to the request. The value can be either:
 // - string
 // - []string
@@ -1,15 +1,20 @@
 package gintestutil
 
 import (
+	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type testStringer struct {
@@ -20,29 +25,36 @@ func (s testStringer) String() string {
 	return s.input
 }
 
+type testStruct struct {
+	Name string `json:"name" xml:"name" yaml:"name" toml:"name" codec:"name"`
+}
+
 func TestPrepareRequest_CreatesExpectedContext(t *testing.T) {
 	t.Parallel()
 	tests := map[string]struct {
 		options []RequestOption
 
-		expectedBody    string
-		expectedUrl     string
-		expectedMethod  string
-		expectedParams  gin.Params
-		expectedHeaders http.Header
-		expectedError   error
+		expectedBody     string
+		expectedUrl      string
+		expectedMethod   string
+		expectedParams   gin.Params
+		expectedHeaders  http.Header
+		expectedFullPath string
+		expectedError    error
 	}{
 		"empty request": {
 			options: []RequestOption{},
 
-			expectedMethod: http.MethodGet,
-			expectedUrl:    "https://example.com",
+			expectedMethod:  http.MethodGet,
+			expectedUrl:     "https://example.com",
+			expectedHeaders: http.Header{},
 		},
 		"method post": {
 			options: []RequestOption{WithMethod(http.MethodPost)},
 
-			expectedMethod: http.MethodPost,
-			expectedUrl:    "https://example.com",
+			expectedMethod:  http.MethodPost,
+			expectedUrl:     "https://example.com",
+			expectedHeaders: http.Header{},
 		},
 		"with url params": {
 			options: []RequestOption{
@@ -69,6 +81,53 @@ func TestPrepareRequest_CreatesExpectedContext(t *testing.T) {
 					Value: "five",
 				},
 			},
+			expectedHeaders: http.Header{},
+		},
+		"with route": {
+			options: []RequestOption{
+				WithMethod(http.MethodDelete),
+				WithRoute("/users/:id/orders/*rest", map[string]any{
+					"id":   testStringer{input: "5"},
+					"rest": "a b/c",
+				}),
+			},
+
+			expectedMethod:   http.MethodDelete,
+			expectedUrl:      "https://example.com/users/5/orders/a%20b/c",
+			expectedFullPath: "/users/:id/orders/*rest",
+			expectedParams: []gin.Param{
+				{
+					Key:   "id",
+					Value: "5",
+				},
+				{
+					Key:   "rest",
+					Value: "/a b/c",
+				},
+			},
+			expectedHeaders: http.Header{},
+		},
+		"with route and url params": {
+			options: []RequestOption{
+				WithUrl("https://maarten.dev/ignored"),
+				WithRoute("/users/:id", map[string]any{"id": "a?b"}),
+				WithUrlParams(map[string]any{"other": "value"}),
+			},
+
+			expectedMethod:   http.MethodGet,
+			expectedUrl:      "https://maarten.dev/users/a%3Fb",
+			expectedFullPath: "/users/:id",
+			expectedParams: []gin.Param{
+				{
+					Key:   "id",
+					Value: "a?b",
+				},
+				{
+					Key:   "other",
+					Value: "value",
+				},
+			},
+			expectedHeaders: http.Header{},
 		},
 		"with query params": {
 			options: []RequestOption{
@@ -79,8 +138,9 @@ func TestPrepareRequest_CreatesExpectedContext(t *testing.T) {
 				}),
 			},
 
-			expectedMethod: http.MethodGet,
-			expectedUrl:    "https://maarten.dev?one=two&three=four&three=five",
+			expectedMethod:  http.MethodGet,
+			expectedUrl:     "https://maarten.dev?one=two&three=four&three=five",
+			expectedHeaders: http.Header{},
 		},
 		"with headers": {
 			options: []RequestOption{
@@ -97,22 +157,65 @@ func TestPrepareRequest_CreatesExpectedContext(t *testing.T) {
 		"maarten.dev url": {
 			options: []RequestOption{WithUrl("https://maarten.dev")},
 
-			expectedUrl:    "https://maarten.dev",
-			expectedMethod: http.MethodGet,
+			expectedUrl:     "https://maarten.dev",
+			expectedMethod:  http.MethodGet,
+			expectedHeaders: http.Header{},
 		},
 		"json body": {
 			options: []RequestOption{WithJsonBody(t, map[string]any{"one": "two", "three": 4})},
 
 			expectedMethod: http.MethodGet,
 			expectedUrl:    "https://example.com",
-			expectedBody:   `{"one": "two", "three": 4}`,
+			expectedBody:   `{"one":"two","three":4}`,
+			expectedHeaders: http.Header{
+				"Content-Type":   []string{"application/json"},
+				"Content-Length": []string{"23"},
+			},
 		},
-		"raw body": {
-			options: []RequestOption{WithBody([]byte("a b c"))},
+		"form body": {
+			options: []RequestOption{WithFormBody(url.Values{"one": []string{"two"}})},
+
+			expectedMethod: http.MethodGet,
+			expectedUrl:    "https://example.com",
+			expectedBody:   "one=two",
+			expectedHeaders: http.Header{
+				"Content-Type":   []string{"application/x-www-form-urlencoded"},
+				"Content-Length": []string{"7"},
+			},
+		},
+		"form body with content type header": {
+			options: []RequestOption{
+				WithFormBody(url.Values{"one": []string{"two"}}),
+				WithHeaders(http.Header{"Content-Type": []string{"text/plain"}}),
+			},
 
 			expectedMethod: http.MethodGet,
 			expectedUrl:    "https://example.com",
-			expectedBody:   "a b c",
+			expectedBody:   "one=two",
+			expectedHeaders: http.Header{
+				"Content-Type":   []string{"text/plain"},
+				"Content-Length": []string{"7"},
+			},
+		},
+		"json body without content headers": {
+			options: []RequestOption{
+				WithJsonBody(t, map[string]any{"one": "two"}),
+				WithoutContentHeaders(),
+				WithHeaders(http.Header{"Content-Type": []string{"text/xml"}}),
+			},
+
+			expectedMethod:  http.MethodGet,
+			expectedUrl:     "https://example.com",
+			expectedBody:    `{"one":"two"}`,
+			expectedHeaders: http.Header{"Content-Type": []string{"text/xml"}},
+		},
+		"raw body": {
+			options: []RequestOption{WithBody([]byte("a b c"))},
+
+			expectedMethod:  http.MethodGet,
+			expectedUrl:     "https://example.com",
+			expectedBody:    "a b c",
+			expectedHeaders: http.Header{"Content-Length": []string{"5"}},
 		},
 		"expected error on nonsensical request": {
 			options:       []RequestOption{WithUrl("://://::::///::::")},
@@ -144,18 +247,229 @@ func TestPrepareRequest_CreatesExpectedContext(t *testing.T) {
 			assert.Equal(t, testData.expectedMethod, context.Request.Method)
 			assert.Equal(t, testData.expectedUrl, context.Request.URL.String())
 			assert.Equal(t, testData.expectedHeaders, context.Request.Header)
+			assert.Equal(t, testData.expectedFullPath, context.FullPath())
 
 			assert.ElementsMatch(t, testData.expectedParams, context.Params)
 
 			if testData.expectedBody != "" {
-				if body, err := io.ReadAll(context.Request.Body); err != nil {
-					assert.Equal(t, []byte(testData.expectedBody), body)
+				if body, err := io.ReadAll(context.Request.Body); assert.NoError(t, err) {
+					assert.Equal(t, testData.expectedBody, string(body))
+					assert.Equal(t, int64(len(body)), context.Request.ContentLength)
 				}
 			}
 		})
 	}
 }
 
+func TestPrepareRequest_ReportsInvalidRoutes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		route  string
+		params map[string]any
+
+		expectedErrors []string
+	}{
+		"missing parameter": {
+			route:  "/users/:id/orders/:order",
+			params: map[string]any{"id": "5"},
+
+			expectedErrors: []string{"route /users/:id/orders/:order is missing a value for parameter 'order'"},
+		},
+		"extra parameter": {
+			route:  "/users/:id",
+			params: map[string]any{"id": "5", "order": "6"},
+
+			expectedErrors: []string{"route parameter 'order' is not part of route /users/:id"},
+		},
+		"unsupported type": {
+			route:  "/users/:id",
+			params: map[string]any{"id": 5},
+
+			expectedErrors: []string{"route parameter 'id' has unsupported type int"},
+		},
+		"invalid route": {
+			route:  "/users/*rest/more",
+			params: map[string]any{"rest": "a"},
+
+			expectedErrors: []string{"failed to register route /users/*rest/more: catch-all routes are only allowed at the end of the path in path '/users/*rest/more'"},
+		},
+		"route does not start with a slash": {
+			route: "users",
+
+			expectedErrors: []string{"url https://example.com/users does not match route users"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			mockT := new(mockT)
+
+			// Act
+			_, writer := PrepareRequest(mockT, WithRoute(testData.route, testData.params))
+
+			// Assert
+			assert.NotNil(t, writer)
+			assert.Equal(t, testData.expectedErrors, mockT.ErrorfCalls)
+		})
+	}
+}
+
+func TestPrepareRequest_RouteWritesToWriter(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	context, writer := PrepareRequest(t, WithRoute("/users/:id", map[string]any{"id": "5"}))
+
+	// Act
+	context.String(http.StatusCreated, "%s %s", context.FullPath(), context.Param("id"))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, writer.Code)
+	assert.Equal(t, "/users/:id 5", writer.Body.String())
+}
+
+func TestWithFormBody_CanBeReadByGin(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	context, _ := PrepareRequest(t,
+		WithMethod(http.MethodPost),
+		WithFormBody(url.Values{"name": []string{"gopher"}, "tags": []string{"a", "b"}}),
+	)
+
+	// Act
+	name := context.PostForm("name")
+	tags := context.PostFormArray("tags")
+
+	// Assert
+	assert.Equal(t, "gopher", name)
+	assert.Equal(t, []string{"a", "b"}, tags)
+}
+
+func TestWithJsonBody_CanBeBoundByGin(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	context, _ := PrepareRequest(t,
+		WithMethod(http.MethodPost),
+		WithJsonBody(t, testStruct{Name: "gopher"}),
+	)
+
+	var result testStruct
+
+	// Act
+	err := context.ShouldBind(&result)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", context.ContentType())
+	assert.Equal(t, testStruct{Name: "gopher"}, result)
+}
+
+func TestBodyOptions_CanBeBoundByGin(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		option func(TestingT, any) RequestOption
+
+		expectedContentType string
+	}{
+		"json": {
+			option:              WithJsonBody,
+			expectedContentType: "application/json",
+		},
+		"xml": {
+			option:              WithXmlBody,
+			expectedContentType: "application/xml",
+		},
+		"yaml": {
+			option:              WithYamlBody,
+			expectedContentType: "application/x-yaml",
+		},
+		"toml": {
+			option:              WithTomlBody,
+			expectedContentType: "application/toml",
+		},
+		"msgpack": {
+			option:              WithMsgPackBody,
+			expectedContentType: "application/x-msgpack",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			mockT := new(mockT)
+			context, _ := PrepareRequest(mockT,
+				WithMethod(http.MethodPost),
+				testData.option(mockT, testStruct{Name: "gopher"}),
+			)
+
+			var result testStruct
+
+			// Act
+			err := context.ShouldBind(&result)
+
+			// Assert
+			assert.Empty(t, mockT.ErrorCalls)
+			assert.NoError(t, err)
+			assert.Equal(t, testData.expectedContentType, context.ContentType())
+			assert.Equal(t, testStruct{Name: "gopher"}, result)
+		})
+	}
+}
+
+func TestWithProtoBody_CanBeBoundByGin(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	mockT := new(mockT)
+	context, _ := PrepareRequest(mockT,
+		WithMethod(http.MethodPost),
+		WithProtoBody(mockT, wrapperspb.String("gopher")),
+	)
+
+	result := new(wrapperspb.StringValue)
+
+	// Act
+	err := context.ShouldBind(result)
+
+	// Assert
+	assert.Empty(t, mockT.ErrorCalls)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/x-protobuf", context.ContentType())
+	assert.Equal(t, "gopher", result.GetValue())
+}
+
+func TestBodyOptions_CallErrorOnFaultyInput(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		option func(TestingT, any) RequestOption
+	}{
+		"xml": {
+			option: WithXmlBody,
+		},
+		"toml": {
+			option: WithTomlBody,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			mock := new(mockT)
+
+			// Act
+			_ = testData.option(mock, func() {})
+
+			// Assert
+			assert.Len(t, mock.ErrorCalls, 1)
+		})
+	}
+}
+
 func TestWithJsonBody_CallsErrorOnFaultyJson(t *testing.T) {
 	t.Parallel()
 	// Arrange
@@ -280,3 +594,399 @@ func TestApplyQueryParams_SetsExpectedValues(t *testing.T) {
 		})
 	}
 }
+
+func TestServe_RunsMiddlewareAndHandlers(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		options []RequestOption
+
+		expectedCode   int
+		expectedBody   string
+		expectedKeys   map[string]any
+		expectedParams gin.Params
+		expectedErrors []string
+	}{
+		"authorized": {
+			options: []RequestOption{
+				WithMethod(http.MethodPut),
+				WithRoute("/users/:id", map[string]any{"id": "5"}),
+				WithHeaders(http.Header{"Authorization": []string{"token"}}),
+				WithJsonBody(t, testStruct{Name: "John"}),
+			},
+			expectedCode:   http.StatusOK,
+			expectedBody:   `{"name":"John"}`,
+			expectedKeys:   map[string]any{"user": "token"},
+			expectedParams: gin.Params{{Key: "id", Value: "5"}},
+			expectedErrors: []string{"user 5 was updated"},
+		},
+		"unauthorized": {
+			options:        []RequestOption{WithMethod(http.MethodPut), WithUrl("/users/5")},
+			expectedCode:   http.StatusUnauthorized,
+			expectedParams: gin.Params{{Key: "id", Value: "5"}},
+		},
+		"unknown route": {
+			options:      []RequestOption{WithUrl("/unknown"), WithHeaders(http.Header{"Authorization": []string{"token"}})},
+			expectedCode: http.StatusNotFound,
+			expectedBody: "404 page not found",
+			expectedKeys: map[string]any{"user": "token"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				if c.GetHeader("Authorization") == "" {
+					c.AbortWithStatus(http.StatusUnauthorized)
+
+					return
+				}
+
+				c.Set("user", c.GetHeader("Authorization"))
+			})
+
+			engine.PUT("/users/:id", func(c *gin.Context) {
+				var user testStruct
+				_ = c.ShouldBindJSON(&user)
+				_ = c.Error(fmt.Errorf("user %s was updated", c.Param("id")))
+				c.JSON(http.StatusOK, user)
+			})
+
+			// Act
+			context, recorder := Serve(testObject, engine, testData.options...)
+
+			// Assert
+			assert.Empty(t, testObject.ErrorfCalls)
+			assert.Equal(t, testData.expectedCode, recorder.Code)
+			assert.Equal(t, testData.expectedBody, recorder.Body.String())
+			assert.Equal(t, testData.expectedKeys, context.Keys)
+			assert.Equal(t, testData.expectedParams, context.Params)
+			assert.Equal(t, testData.expectedErrors, context.Errors.Errors())
+		})
+	}
+}
+
+func TestServe_DefaultUrlReachesRootRoute(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "root")
+	})
+
+	// Act
+	_, recorder := Serve(testObject, engine)
+
+	// Assert
+	assert.Empty(t, testObject.ErrorCalls)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "root", recorder.Body.String())
+}
+
+func TestServe_FailsOnNilEngine(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	context, _ := Serve(testObject, nil)
+
+	// Assert
+	assert.Nil(t, context)
+	assert.Equal(t, []any{"engine cannot be nil"}, testObject.ErrorCalls)
+}
+
+// exampleHandler is used to verify the handler name of the context
+func exampleHandler(c *gin.Context) {
+	c.Status(http.StatusTeapot)
+}
+
+func TestPrepareRequest_SeedsContextState(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	context, writer := PrepareRequest(testObject,
+		WithUrl("https://example.com/users/5"),
+		WithContextKeys(map[string]any{"tenant": "ing"}),
+		WithContextErrors(errors.New("first"), errors.New("second")),
+		WithHandlerName(exampleHandler),
+		WithFullPath("/users/:id"),
+		WithUrlParams(map[string]any{"id": "5"}),
+	)
+
+	// Assert
+	assert.Empty(t, testObject.ErrorfCalls)
+	assert.Equal(t, "ing", context.MustGet("tenant"))
+	assert.Equal(t, []string{"first", "second"}, context.Errors.Errors())
+	assert.Equal(t, "github.com/ing-bank/gintestutil.exampleHandler", context.HandlerName())
+	assert.Equal(t, "/users/:id", context.FullPath())
+	assert.Equal(t, "https://example.com/users/5", context.Request.URL.String())
+	assert.Equal(t, gin.Params{{Key: "id", Value: "5"}}, context.Params)
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestWithFullPath_SetsFullPath(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		options []RequestOption
+
+		expected       string
+		expectedParams gin.Params
+	}{
+		"param": {
+			options:  []RequestOption{WithFullPath("/users/:id")},
+			expected: "/users/:id",
+		},
+		"catch-all": {
+			options:  []RequestOption{WithFullPath("/files/*path")},
+			expected: "/files/*path",
+		},
+		"overrides route": {
+			options:        []RequestOption{WithRoute("/users/:id", map[string]any{"id": "5"}), WithFullPath("/accounts/:id")},
+			expected:       "/accounts/:id",
+			expectedParams: gin.Params{{Key: "id", Value: "5"}},
+		},
+		"conflicting route": {
+			options:        []RequestOption{WithRoute("/users/:id", map[string]any{"id": "5"}), WithFullPath("/users/:name")},
+			expected:       "/users/:name",
+			expectedParams: gin.Params{{Key: "id", Value: "5"}},
+		},
+		"handler name only": {
+			options:  []RequestOption{WithUrl("https://example.com/health"), WithHandlerName(exampleHandler)},
+			expected: "/health",
+		},
+		"handler name with default url": {
+			options:  []RequestOption{WithHandlerName(exampleHandler)},
+			expected: "/",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+
+			// Act
+			context, _ := PrepareRequest(testObject, testData.options...)
+
+			// Assert
+			assert.Empty(t, testObject.ErrorfCalls)
+			assert.Equal(t, testData.expected, context.FullPath())
+			assert.Equal(t, testData.expectedParams, context.Params)
+		})
+	}
+}
+
+func TestWithContextErrors_FailsOnNilError(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	context, _ := PrepareRequest(testObject, WithContextErrors(nil, errors.New("error")))
+
+	// Assert
+	assert.Equal(t, []any{"context errors cannot be nil"}, testObject.ErrorCalls)
+	assert.Equal(t, []string{"error"}, context.Errors.Errors())
+}
+
+// contextKey is used for values in the context of requests
+type contextKey string
+
+func TestContextOptions_SetRequestContext(t *testing.T) {
+	t.Parallel()
+	deadline := time.Now().Add(time.Hour)
+
+	tests := map[string]struct {
+		options []RequestOption
+
+		expectedValue    any
+		expectedDeadline time.Time
+		expectedErr      error
+		expectedCleanups int
+	}{
+		"values": {
+			options:       []RequestOption{WithContext(context.WithValue(context.Background(), contextKey("trace"), "abc"))},
+			expectedValue: "abc",
+		},
+		"deadline": {
+			options:          []RequestOption{WithDeadline(deadline)},
+			expectedDeadline: deadline,
+			expectedCleanups: 1,
+		},
+		"cancelled": {
+			options:     []RequestOption{WithCancelledContext()},
+			expectedErr: context.Canceled,
+		},
+		"values with deadline": {
+			options: []RequestOption{
+				WithContext(context.WithValue(context.Background(), contextKey("trace"), "abc")),
+				WithDeadline(deadline),
+			},
+			expectedValue:    "abc",
+			expectedDeadline: deadline,
+			expectedCleanups: 1,
+		},
+		"expired deadline": {
+			options:          []RequestOption{WithDeadline(deadline.Add(-2 * time.Hour))},
+			expectedDeadline: deadline.Add(-2 * time.Hour),
+			expectedErr:      context.DeadlineExceeded,
+			expectedCleanups: 1,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+
+			// Act
+			ginContext, _ := PrepareRequest(testObject, testData.options...)
+
+			// Assert
+			ctx := ginContext.Request.Context()
+			actualDeadline, _ := ctx.Deadline()
+
+			assert.Empty(t, testObject.ErrorCalls)
+			assert.Equal(t, testData.expectedValue, ctx.Value(contextKey("trace")))
+			assert.Equal(t, testData.expectedDeadline, actualDeadline)
+			assert.Equal(t, testData.expectedErr, ctx.Err())
+			assert.Len(t, testObject.Cleanups, testData.expectedCleanups)
+		})
+	}
+}
+
+func TestWithCancelledContext_CanBeHandledByEngine(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	engine := gin.New()
+	engine.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.Status(499)
+
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	// Act
+	_, recorder := Serve(testObject, engine, WithUrl("/slow"), WithCancelledContext())
+
+	// Assert
+	assert.Equal(t, 499, recorder.Code)
+}
+
+func TestWithContext_FailsOnNilContext(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	//nolint:staticcheck // A nil context is what's being tested
+	_, _ = PrepareRequest(testObject, WithContext(nil))
+
+	// Assert
+	assert.Equal(t, []any{"context cannot be nil"}, testObject.ErrorCalls)
+}
+
+func TestClientOptions_SetClientIP(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		options        []RequestOption
+		trustedProxies []string
+
+		expectedRemoteIP string
+		expectedClientIP string
+	}{
+		"no address": {},
+		"address without port": {
+			options:          []RequestOption{WithRemoteAddr("10.0.0.1")},
+			expectedRemoteIP: "10.0.0.1",
+			expectedClientIP: "10.0.0.1",
+		},
+		"ipv6 address with port": {
+			options:          []RequestOption{WithRemoteAddr("[::1]:8080")},
+			expectedRemoteIP: "::1",
+			expectedClientIP: "::1",
+		},
+		"forwarded by trusted proxy": {
+			options:          []RequestOption{WithRemoteAddr("10.0.0.1"), WithForwardedFor("203.0.113.5", "10.0.0.2")},
+			trustedProxies:   []string{"10.0.0.0/8"},
+			expectedRemoteIP: "10.0.0.1",
+			expectedClientIP: "203.0.113.5",
+		},
+		"forwarded by untrusted proxy": {
+			options:          []RequestOption{WithRemoteAddr("192.168.0.1"), WithForwardedFor("203.0.113.5")},
+			trustedProxies:   []string{"10.0.0.0/8"},
+			expectedRemoteIP: "192.168.0.1",
+			expectedClientIP: "192.168.0.1",
+		},
+		"header takes precedence": {
+			options: []RequestOption{
+				WithRemoteAddr("10.0.0.1"),
+				WithForwardedFor("203.0.113.5"),
+				WithHeaders(http.Header{"X-Forwarded-For": []string{"198.51.100.7"}}),
+			},
+			trustedProxies:   []string{"10.0.0.0/8"},
+			expectedRemoteIP: "10.0.0.1",
+			expectedClientIP: "198.51.100.7",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			engine := gin.New()
+			_ = engine.SetTrustedProxies(testData.trustedProxies)
+
+			var remoteIP, clientIP string
+			engine.GET("/", func(c *gin.Context) {
+				remoteIP = c.RemoteIP()
+				clientIP = c.ClientIP()
+			})
+
+			// Act
+			Serve(testObject, engine, testData.options...)
+
+			// Assert
+			assert.Empty(t, testObject.ErrorCalls)
+			assert.Equal(t, testData.expectedRemoteIP, remoteIP)
+			assert.Equal(t, testData.expectedClientIP, clientIP)
+		})
+	}
+}
+
+func TestWithTLS_SetsPeerCertificates(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	certificate := SelfSignedCertificate(testObject, "client")
+
+	// Act
+	context, _ := PrepareRequest(testObject, WithTLS(certificate), WithRemoteAddr("10.0.0.1:443"))
+
+	// Assert
+	assert.Empty(t, testObject.ErrorfCalls)
+	if assert.NotNil(t, context.Request.TLS) {
+		assert.True(t, context.Request.TLS.HandshakeComplete)
+		assert.Equal(t, []*x509.Certificate{certificate}, context.Request.TLS.PeerCertificates)
+	}
+
+	assert.Equal(t, "10.0.0.1", context.ClientIP())
+}
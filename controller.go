@@ -1,11 +1,65 @@
 package gintestutil
 
 import (
-	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin/binding"
 )
 
+// ResponseOption allows various options to be supplied to Response
+type ResponseOption func(*responseConfig)
+
+// ExpectContentType is used to verify the media type of the response before decoding it, parameters
+// such as the charset are ignored
+func ExpectContentType(mediaType string) ResponseOption {
+	return func(config *responseConfig) {
+		config.contentType = mediaType
+	}
+}
+
+// Strict makes json decoding fail on fields that don't exist in the result and on data after the json document, this
+// catches fields that are accidentally added to a response. Other media types are not affected.
+func Strict() ResponseOption {
+	return func(config *responseConfig) {
+		config.strict = true
+	}
+}
+
+// RequireFields enables Strict and additionally fails when fields of the result are missing from the json body,
+// fields tagged with omitempty are optional.
+func RequireFields() ResponseOption {
+	return func(config *responseConfig) {
+		config.strict = true
+		config.requireFields = true
+	}
+}
+
+type responseConfig struct {
+	contentType   string
+	strict        bool
+	requireFields bool
+}
+
+// maxBodyLength is the maximum length of a body in an error message before it is truncated
+const maxBodyLength = 1000
+
+// truncateString shortens long strings in error messages, noting how much was left out
+func truncateString(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+
+	return fmt.Sprintf("%s... (%d more bytes)", value[:length], len(value)-length)
+}
+
+// truncateBody is used to keep error messages with large bodies readable
+func truncateBody(body []byte) string {
+	return truncateString(string(body), maxBodyLength)
+}
+
 // statusHasBody is used to determine whether a response is allowed to have a body
 func statusHasBody(status int) bool {
 	switch {
@@ -24,15 +78,38 @@ func statusHasBody(status int) bool {
 
 // Response checks the status code and unmarshalls it to the given type.
 // If you don't care about the response, Use nil. If the return code is 204 or 304, the response body is not converted.
-func Response(t TestingT, result any, code int, res *http.Response) bool {
+//
+// The decoder is picked based on the Content-Type of the response, json is assumed if it is missing. Decoders
+// for json, xml, yaml, toml, msgpack and protobuf are available and plain text can be decoded into a *string. A *[]byte
+// always receives the raw body. Use RegisterDecoder to support other media types. Media types without a decoder,
+// and plain text that isn't decoded into a *string, are decoded as json unless ExpectContentType is given.
+func Response(t TestingT, result any, code int, res *http.Response, options ...ResponseOption) bool {
 	t.Helper()
 
+	config := &responseConfig{}
+
+	for _, option := range options {
+		option(config)
+	}
+
 	if code != res.StatusCode {
 		t.Errorf("Status code %d is not %d", res.StatusCode, code)
 
 		return false
 	}
 
+	mediaType := parseMediaType(res.Header.Get("Content-Type"))
+	if config.contentType != "" && parseMediaType(config.contentType) != mediaType {
+		t.Errorf("Content-Type '%s' is not '%s'", res.Header.Get("Content-Type"), config.contentType)
+
+		return false
+	}
+
+	// Responses without a Content-Type have always been treated as json
+	if mediaType == "" {
+		mediaType = binding.MIMEJSON
+	}
+
 	response, err := io.ReadAll(res.Body)
 	if err != nil {
 		t.Errorf("failed to read body of response")
@@ -45,11 +122,53 @@ func Response(t TestingT, result any, code int, res *http.Response) bool {
 		return true
 	}
 
-	if err := json.Unmarshal(response, &result); err != nil {
-		t.Errorf("Failed to unmarshall '%s' into '%T': %v", response, result, err)
+	if raw, ok := result.(*[]byte); ok {
+		*raw = response
+
+		return true
+	}
+
+	decoder, ok := decoders.get(mediaType)
+	if !ok && config.contentType != "" {
+		t.Errorf("No decoder registered for Content-Type %s", mediaType)
+
+		return false
+	}
+
+	// Responses have always been decoded as json, which is kept for unknown types and plain text that isn't
+	// decoded into a *string, such as json written with c.String
+	if _, isString := result.(*string); !ok || (mediaType == binding.MIMEPlain && !isString) {
+		mediaType = binding.MIMEJSON
+		decoder, _ = decoders.get(mediaType)
+	}
+
+	if config.strict && isJsonMediaType(mediaType) {
+		decoder = strictJsonDecoder(config.requireFields)
+	}
+
+	if err := decoder(response, result); err != nil {
+		t.Errorf("Failed to unmarshall '%s' into '%T': %v", truncateBody(response), result, err)
 
 		return false
 	}
 
 	return true
 }
+
+// ResponseAs checks the status code and returns the body decoded into a new T, which makes it easy to compare the
+// result inline. It behaves exactly like Response, so the zero value is returned if the status code doesn't allow a body.
+func ResponseAs[T any](t TestingT, code int, res *http.Response, options ...ResponseOption) (T, bool) {
+	t.Helper()
+
+	var result T
+	ok := Response(t, &result, code, res, options...)
+
+	return result, ok
+}
+
+// RecorderAs is ResponseAs for the *httptest.ResponseRecorder returned by PrepareRequest
+func RecorderAs[T any](t TestingT, code int, recorder *httptest.ResponseRecorder, options ...ResponseOption) (T, bool) {
+	t.Helper()
+
+	return ResponseAs[T](t, code, recorder.Result(), options...)
+}
@@ -8,7 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type testObject struct {
@@ -138,3 +142,356 @@ func TestSuccessResponse_FailsOnUnmarshall(t *testing.T) {
 	assert.Empty(t, result)
 	assert.False(t, ok)
 }
+
+func TestResponse_DecodesBasedOnContentType(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		render func(*gin.Context)
+		result func() any
+
+		expected any
+	}{
+		"json": {
+			render:   func(c *gin.Context) { c.JSON(http.StatusOK, testStruct{Name: "gopher"}) },
+			result:   func() any { return new(testStruct) },
+			expected: &testStruct{Name: "gopher"},
+		},
+		"xml": {
+			render:   func(c *gin.Context) { c.XML(http.StatusOK, testStruct{Name: "gopher"}) },
+			result:   func() any { return new(testStruct) },
+			expected: &testStruct{Name: "gopher"},
+		},
+		"yaml": {
+			render:   func(c *gin.Context) { c.YAML(http.StatusOK, testStruct{Name: "gopher"}) },
+			result:   func() any { return new(testStruct) },
+			expected: &testStruct{Name: "gopher"},
+		},
+		"toml": {
+			render:   func(c *gin.Context) { c.TOML(http.StatusOK, testStruct{Name: "gopher"}) },
+			result:   func() any { return new(testStruct) },
+			expected: &testStruct{Name: "gopher"},
+		},
+		"msgpack": {
+			render:   func(c *gin.Context) { c.Render(http.StatusOK, render.MsgPack{Data: testStruct{Name: "gopher"}}) },
+			result:   func() any { return new(testStruct) },
+			expected: &testStruct{Name: "gopher"},
+		},
+		"protobuf": {
+			render:   func(c *gin.Context) { c.ProtoBuf(http.StatusOK, wrapperspb.String("gopher")) },
+			result:   func() any { return new(wrapperspb.StringValue) },
+			expected: "gopher",
+		},
+		"plain text": {
+			render:   func(c *gin.Context) { c.String(http.StatusOK, "hello %s", "gopher") },
+			result:   func() any { return new(string) },
+			expected: func() *string { result := "hello gopher"; return &result }(),
+		},
+		"raw": {
+			render:   func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte{1, 2, 3}) },
+			result:   func() any { return new([]byte) },
+			expected: &[]byte{1, 2, 3},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			testData.render(context)
+
+			result := testData.result()
+
+			// Act
+			ok := Response(testingObject, result, http.StatusOK, writer.Result())
+
+			// Assert
+			assert.True(t, ok)
+			assert.Empty(t, testingObject.ErrorfCalls)
+
+			if message, isProto := result.(*wrapperspb.StringValue); isProto {
+				assert.Equal(t, testData.expected, message.GetValue())
+
+				return
+			}
+
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestResponse_FailsOnContentTypeMismatch(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.XML(http.StatusOK, testStruct{Name: "gopher"})
+
+	var result testStruct
+
+	// Act
+	ok := Response(testingObject, &result, http.StatusOK, writer.Result(), ExpectContentType(binding.MIMEJSON))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{"Content-Type 'application/xml; charset=utf-8' is not 'application/json'"}, testingObject.ErrorfCalls)
+}
+
+func TestResponse_SucceedsOnContentTypeMatch(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.JSON(http.StatusOK, testStruct{Name: "gopher"})
+
+	var result testStruct
+
+	// Act
+	ok := Response(testingObject, &result, http.StatusOK, writer.Result(), ExpectContentType(binding.MIMEJSON))
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, testStruct{Name: "gopher"}, result)
+}
+
+func TestResponse_DecodesUnknownContentTypesAsJson(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		contentType string
+	}{
+		"unknown": {
+			contentType: "application/x-unknown",
+		},
+		"octet stream": {
+			contentType: "application/octet-stream",
+		},
+		"plain text": {
+			contentType: binding.MIMEPlain,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			context.Data(http.StatusOK, testData.contentType, []byte(`{"name":"gopher"}`))
+
+			var result testStruct
+
+			// Act
+			ok := Response(testingObject, &result, http.StatusOK, writer.Result())
+
+			// Assert
+			assert.True(t, ok)
+			assert.Empty(t, testingObject.ErrorfCalls)
+			assert.Equal(t, testStruct{Name: "gopher"}, result)
+		})
+	}
+}
+
+func TestResponse_FailsOnExpectedContentTypeWithoutDecoder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.Data(http.StatusOK, "application/x-unknown", []byte("?"))
+
+	var result testStruct
+
+	// Act
+	ok := Response(testingObject, &result, http.StatusOK, writer.Result(), ExpectContentType("application/x-unknown"))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{"No decoder registered for Content-Type application/x-unknown"}, testingObject.ErrorfCalls)
+}
+
+func TestResponseAs_ReturnsDecodedValue(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.JSON(http.StatusOK, []testStruct{{Name: "gopher"}})
+
+	// Act
+	result, ok := ResponseAs[[]testStruct](testingObject, http.StatusOK, writer.Result())
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, []testStruct{{Name: "gopher"}}, result)
+}
+
+func TestResponseAs_FailsOnWrongStatusCode(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.JSON(http.StatusBadRequest, testStruct{Name: "gopher"})
+
+	// Act
+	result, ok := ResponseAs[testStruct](testingObject, http.StatusOK, writer.Result())
+
+	// Assert
+	assert.False(t, ok)
+	assert.Empty(t, result)
+	assert.Equal(t, []string{"Status code 400 is not 200"}, testingObject.ErrorfCalls)
+}
+
+func TestRecorderAs_ReturnsDecodedValue(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		code   int
+		render func(*gin.Context)
+
+		expected   testStruct
+		expectedOk bool
+	}{
+		"ok": {
+			code:       http.StatusOK,
+			render:     func(c *gin.Context) { c.JSON(http.StatusOK, testStruct{Name: "gopher"}) },
+			expected:   testStruct{Name: "gopher"},
+			expectedOk: true,
+		},
+		"no content": {
+			code:       http.StatusNoContent,
+			render:     func(c *gin.Context) { c.Status(http.StatusNoContent) },
+			expectedOk: true,
+		},
+		"broken json": {
+			code:   http.StatusOK,
+			render: func(c *gin.Context) { c.Data(http.StatusOK, binding.MIMEJSON, []byte("{{")) },
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			testData.render(context)
+			context.Writer.WriteHeaderNow()
+
+			// Act
+			result, ok := RecorderAs[testStruct](testingObject, testData.code, writer)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestResponse_StrictDecoding(t *testing.T) {
+	t.Parallel()
+	type nested struct {
+		ID       int    `json:"id"`
+		Optional string `json:"optional,omitempty"`
+	}
+
+	type strictObject struct {
+		Name  string   `json:"name"`
+		Items []nested `json:"items"`
+	}
+
+	tests := map[string]struct {
+		body    string
+		options []ResponseOption
+
+		expectedOk     bool
+		expectedErrorf string
+	}{
+		"unknown field is ignored by default": {
+			body:       `{"name": "a", "items": [], "secret": "b"}`,
+			expectedOk: true,
+		},
+		"unknown field fails in strict mode": {
+			body:           `{"name": "a", "items": [], "secret": "b"}`,
+			options:        []ResponseOption{Strict()},
+			expectedErrorf: `json: unknown field "secret"`,
+		},
+		"trailing data fails in strict mode": {
+			body:           `{"name": "a", "items": []} {}`,
+			options:        []ResponseOption{Strict()},
+			expectedErrorf: "unexpected data after json document",
+		},
+		"trailing whitespace succeeds in strict mode": {
+			body:       "{\"name\": \"a\", \"items\": []}\n",
+			options:    []ResponseOption{Strict()},
+			expectedOk: true,
+		},
+		"missing field succeeds in strict mode": {
+			body:       `{"items": [{}]}`,
+			options:    []ResponseOption{Strict()},
+			expectedOk: true,
+		},
+		"missing fields fail with required fields": {
+			body:           `{"items": [{"id": 1}, {"optional": "x"}]}`,
+			options:        []ResponseOption{RequireFields()},
+			expectedErrorf: "missing fields: $.name, $.items[1].id",
+		},
+		"all fields present succeeds with required fields": {
+			body:       `{"NAME": "a", "items": [{"id": 1}]}`,
+			options:    []ResponseOption{RequireFields()},
+			expectedOk: true,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			context.Data(http.StatusOK, binding.MIMEJSON, []byte(testData.body))
+
+			var result strictObject
+
+			// Act
+			ok := Response(testingObject, &result, http.StatusOK, writer.Result(), testData.options...)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+
+			if testData.expectedErrorf != "" && assert.Len(t, testingObject.ErrorfCalls, 1) {
+				assert.Contains(t, testingObject.ErrorfCalls[0], testData.expectedErrorf)
+			}
+		})
+	}
+}
+
+func TestTruncateString_ShortensLongValues(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"short": {
+			input:    "abc",
+			expected: "abc",
+		},
+		"long": {
+			input:    "abcdef",
+			expected: "abc... (3 more bytes)",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := truncateString(testData.input, 3)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
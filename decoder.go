@@ -0,0 +1,245 @@
+package gintestutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// Decoder decodes a response body into the given target, which is usually a pointer
+type Decoder func(data []byte, target any) error
+
+// decoderRegistry holds the decoders per media type, it is safe for concurrent use since tests run in parallel
+type decoderRegistry struct {
+	mutex    sync.RWMutex
+	decoders map[string]Decoder
+}
+
+// decoders contains the decoders used by Response, extended using RegisterDecoder
+var decoders = &decoderRegistry{
+	decoders: map[string]Decoder{
+		binding.MIMEJSON:     json.Unmarshal,
+		binding.MIMEXML:      xml.Unmarshal,
+		binding.MIMEXML2:     xml.Unmarshal,
+		binding.MIMEYAML:     yaml.Unmarshal,
+		"application/yaml":   yaml.Unmarshal,
+		binding.MIMETOML:     toml.Unmarshal,
+		binding.MIMEMSGPACK:  decodeMsgPack,
+		binding.MIMEMSGPACK2: decodeMsgPack,
+		binding.MIMEPROTOBUF: decodeProtobuf,
+		binding.MIMEPlain:    decodeText,
+	},
+}
+
+// suffixDecoders are used for media types with a structured syntax suffix, such as application/vnd.ing.v2+json
+var suffixDecoders = map[string]string{
+	"+json": binding.MIMEJSON,
+	"+xml":  binding.MIMEXML,
+	"+yaml": binding.MIMEYAML,
+}
+
+// RegisterDecoder registers a decoder for the given media type, replacing any existing one. Media types are
+// matched without parameters, so application/json also handles application/json; charset=utf-8.
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decoders.mutex.Lock()
+	defer decoders.mutex.Unlock()
+
+	decoders.decoders[strings.ToLower(mediaType)] = decoder
+}
+
+// get returns the decoder of a media type, falling back on its structured syntax suffix
+func (r *decoderRegistry) get(mediaType string) (Decoder, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if decoder, ok := r.decoders[mediaType]; ok {
+		return decoder, true
+	}
+
+	for suffix, fallback := range suffixDecoders {
+		if strings.HasSuffix(mediaType, suffix) {
+			decoder, ok := r.decoders[fallback]
+
+			return decoder, ok
+		}
+	}
+
+	return nil, false
+}
+
+// parseMediaType strips the parameters of a Content-Type header
+func parseMediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	return mediaType
+}
+
+// isJsonMediaType returns true for application/json and media types with a +json suffix
+func isJsonMediaType(mediaType string) bool {
+	return mediaType == binding.MIMEJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// strictJsonDecoder returns a json decoder that rejects unknown fields and trailing data, and optionally
+// fields of the target that are missing in the body
+func strictJsonDecoder(requireFields bool) Decoder {
+	return func(data []byte, target any) error {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(target); err != nil {
+			return err
+		}
+
+		if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("unexpected data after json document at offset %d", decoder.InputOffset())
+		}
+
+		if !requireFields {
+			return nil
+		}
+
+		if missing := missingJsonFields(data, reflect.TypeOf(target), "$"); len(missing) > 0 {
+			return fmt.Errorf("missing fields: %s", strings.Join(missing, ", "))
+		}
+
+		return nil
+	}
+}
+
+// missingJsonFields returns the paths of all struct fields without omitempty that are not present in the json data,
+// nested structs, slices and maps are checked as well.
+func missingJsonFields(data json.RawMessage, typ reflect.Type, path string) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var missing []string
+
+	// Other kinds can't contain struct fields
+	switch typ.Kind() {
+	case reflect.Struct:
+		missing = missingStructFields(data, typ, path)
+
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		_ = json.Unmarshal(data, &items)
+
+		for index, item := range items {
+			missing = append(missing, missingJsonFields(item, typ.Elem(), fmt.Sprintf("%s[%d]", path, index))...)
+		}
+
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		_ = json.Unmarshal(data, &items)
+
+		for key, item := range items {
+			missing = append(missing, missingJsonFields(item, typ.Elem(), path+"."+key)...)
+		}
+	}
+
+	return missing
+}
+
+// missingStructFields is the struct part of missingJsonFields, embedded structs are treated like encoding/json does
+func missingStructFields(data json.RawMessage, typ reflect.Type, path string) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return nil
+	}
+
+	var missing []string
+
+	for index := 0; index < typ.NumField(); index++ {
+		field := typ.Field(index)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch {
+		case name == "-":
+			continue
+
+		case field.Anonymous && name == "":
+			missing = append(missing, missingJsonFields(data, field.Type, path)...)
+
+			continue
+
+		case !field.IsExported():
+			continue
+
+		case name == "":
+			name = field.Name
+		}
+
+		value, ok := lookupJsonField(object, name)
+		switch {
+		case ok:
+			missing = append(missing, missingJsonFields(value, field.Type, path+"."+name)...)
+
+		case !strings.Contains(options, "omitempty"):
+			missing = append(missing, path+"."+name)
+		}
+	}
+
+	return missing
+}
+
+// lookupJsonField finds a key in a json object, case-insensitive like encoding/json
+func lookupJsonField(object map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// decodeMsgPack decodes using the same codec as gin
+func decodeMsgPack(data []byte, target any) error {
+	return codec.NewDecoderBytes(data, new(codec.MsgpackHandle)).Decode(target)
+}
+
+// decodeProtobuf decodes into a proto.Message
+func decodeProtobuf(data []byte, target any) error {
+	message, ok := target.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not a proto.Message", target)
+	}
+
+	return proto.Unmarshal(data, message)
+}
+
+// decodeText decodes plain text into a *string
+func decodeText(data []byte, target any) error {
+	result, ok := target.(*string)
+	if !ok {
+		return fmt.Errorf("plain text can only be decoded into *string, not %T", target)
+	}
+
+	*result = string(data)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package gintestutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDecoder_IsUsedForMediaType(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	RegisterDecoder("application/vnd.ING.test", func(data []byte, target any) error {
+		*target.(*string) = "decoded " + string(data)
+
+		return nil
+	})
+
+	context, writer := PrepareRequest(t)
+	context.Data(http.StatusOK, "application/vnd.ing.test; version=2", []byte("body"))
+
+	var result string
+
+	// Act
+	ok := Response(testingObject, &result, http.StatusOK, writer.Result())
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, "decoded body", result)
+}
+
+func TestDecoderRegistry_FallsBackOnSuffix(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		mediaType string
+
+		expectedOk bool
+	}{
+		"json suffix": {
+			mediaType:  "application/vnd.ing.v2+json",
+			expectedOk: true,
+		},
+		"xml suffix": {
+			mediaType:  "application/atom+xml",
+			expectedOk: true,
+		},
+		"unknown suffix": {
+			mediaType:  "application/vnd.ing.v2+zip",
+			expectedOk: false,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			decoder, ok := decoders.get(testData.mediaType)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expectedOk, decoder != nil)
+		})
+	}
+}
+
+func TestParseMediaType_StripsParameters(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"empty": {
+			input:    "",
+			expected: "",
+		},
+		"plain": {
+			input:    "application/json",
+			expected: "application/json",
+		},
+		"with charset": {
+			input:    "Application/JSON; charset=utf-8",
+			expected: "application/json",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := parseMediaType(testData.input)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
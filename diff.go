@@ -0,0 +1,202 @@
+package gintestutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxDifferences is the amount of differences reported by ResponseEqualJson, the rest is summarised
+	maxDifferences = 25
+
+	// maxValueLength is the maximum length of a value in a difference before it is truncated
+	maxValueLength = 80
+)
+
+// identifierPattern matches object keys that can be used in a path without quoting
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ResponseEqualJson checks the status code and semantically compares the json body with the expected value, which is
+// marshalled using json.Marshal, use a json.RawMessage to compare against a literal json document. Key order and
+// number formatting (10 vs 10.0) are ignored and every difference is reported with its path, such as
+// `$.items[3].price: expected 10.5, got 10`.
+func ResponseEqualJson(t TestingT, expected any, code int, res *http.Response, options ...ResponseOption) bool {
+	t.Helper()
+
+	var body []byte
+	if !Response(t, &body, code, res, options...) {
+		return false
+	}
+
+	if !statusHasBody(code) {
+		return true
+	}
+
+	expectedData, err := json.Marshal(expected)
+	if err != nil {
+		t.Errorf("Failed to marshal expected value '%T': %v", expected, err)
+
+		return false
+	}
+
+	expectedValue, err := decodeJsonValue(expectedData)
+	if err != nil {
+		t.Errorf("Failed to unmarshall expected value '%s': %v", truncateBody(expectedData), err)
+
+		return false
+	}
+
+	actualValue, err := decodeJsonValue(body)
+	if err != nil {
+		t.Errorf("Failed to unmarshall '%s' as json: %v", truncateBody(body), err)
+
+		return false
+	}
+
+	differences := diffJson("$", expectedValue, actualValue)
+	if len(differences) == 0 {
+		return true
+	}
+
+	if len(differences) > maxDifferences {
+		differences = append(differences[:maxDifferences], fmt.Sprintf("... and %d more", len(differences)-maxDifferences))
+	}
+
+	t.Errorf("Response body does not match the expected json:\n%s", strings.Join(differences, "\n"))
+
+	return false
+}
+
+// decodeJsonValue decodes json into generic values, keeping numbers as json.Number so they can be compared exactly
+func decodeJsonValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var result any
+	err := decoder.Decode(&result)
+
+	return result, err
+}
+
+// diffJson returns all differences between two decoded json values, annotated with their path
+func diffJson(path string, expected any, actual any) []string {
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		if actualValue, ok := actual.(map[string]any); ok {
+			return diffJsonObjects(path, expectedValue, actualValue)
+		}
+
+	case []any:
+		if actualValue, ok := actual.([]any); ok {
+			return diffJsonArrays(path, expectedValue, actualValue)
+		}
+
+	case json.Number:
+		if actualValue, ok := actual.(json.Number); ok && numbersEqual(expectedValue, actualValue) {
+			return nil
+		}
+
+	default:
+		if expected == actual {
+			return nil
+		}
+	}
+
+	return []string{fmt.Sprintf("%s: expected %s, got %s", path, formatJsonValue(expected), formatJsonValue(actual))}
+}
+
+// diffJsonObjects compares objects key by key in sorted order, so the output is stable
+func diffJsonObjects(path string, expected map[string]any, actual map[string]any) []string {
+	keys := make([]string, 0, len(expected)+len(actual))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var differences []string
+
+	for _, key := range keys {
+		keyPath := jsonKeyPath(path, key)
+		expectedValue, inExpected := expected[key]
+		actualValue, inActual := actual[key]
+
+		switch {
+		case !inActual:
+			differences = append(differences, fmt.Sprintf("%s: expected %s, got nothing", keyPath, formatJsonValue(expectedValue)))
+
+		case !inExpected:
+			differences = append(differences, fmt.Sprintf("%s: unexpected %s", keyPath, formatJsonValue(actualValue)))
+
+		default:
+			differences = append(differences, diffJson(keyPath, expectedValue, actualValue)...)
+		}
+	}
+
+	return differences
+}
+
+// diffJsonArrays compares arrays index by index and reports missing or additional items
+func diffJsonArrays(path string, expected []any, actual []any) []string {
+	var differences []string
+
+	for index := 0; index < len(expected) || index < len(actual); index++ {
+		indexPath := fmt.Sprintf("%s[%d]", path, index)
+
+		switch {
+		case index >= len(actual):
+			differences = append(differences, fmt.Sprintf("%s: expected %s, got nothing", indexPath, formatJsonValue(expected[index])))
+
+		case index >= len(expected):
+			differences = append(differences, fmt.Sprintf("%s: unexpected %s", indexPath, formatJsonValue(actual[index])))
+
+		default:
+			differences = append(differences, diffJson(indexPath, expected[index], actual[index])...)
+		}
+	}
+
+	return differences
+}
+
+// numbersEqual compares json numbers by value, so 10, 10.0 and 1e1 are equal
+func numbersEqual(expected json.Number, actual json.Number) bool {
+	expectedRat, expectedOk := new(big.Rat).SetString(expected.String())
+	actualRat, actualOk := new(big.Rat).SetString(actual.String())
+
+	if !expectedOk || !actualOk {
+		return expected == actual
+	}
+
+	return expectedRat.Cmp(actualRat) == 0
+}
+
+// jsonKeyPath appends a key to the path, quoting it if necessary
+func jsonKeyPath(path string, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+// formatJsonValue formats a decoded value as compact json for use in a difference
+func formatJsonValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return truncateString(string(data), maxValueLength)
+}
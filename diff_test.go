@@ -0,0 +1,157 @@
+package gintestutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJson_ReturnsExpectedDifferences(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		expected string
+		actual   string
+
+		expectedDifferences []string
+	}{
+		"equal objects in different order": {
+			expected: `{"a": 1, "b": [1, 2]}`,
+			actual:   `{"b": [1, 2], "a": 1}`,
+		},
+		"equal numbers in different notation": {
+			expected: `[10, 1.50, 100]`,
+			actual:   `[10.0, 1.5, 1e2]`,
+		},
+		"different number": {
+			expected: `{"items": [{}, {}, {}, {"price": 10.5}]}`,
+			actual:   `{"items": [{}, {}, {}, {"price": 10}]}`,
+
+			expectedDifferences: []string{"$.items[3].price: expected 10.5, got 10"},
+		},
+		"missing and unexpected keys": {
+			expected: `{"a": "b", "my key": true}`,
+			actual:   `{"c": null}`,
+
+			expectedDifferences: []string{
+				`$.a: expected "b", got nothing`,
+				`$.c: unexpected null`,
+				`$["my key"]: expected true, got nothing`,
+			},
+		},
+		"different array lengths": {
+			expected: `[1, 2]`,
+			actual:   `[1, 2, {"a": 3}]`,
+
+			expectedDifferences: []string{`$[2]: unexpected {"a":3}`},
+		},
+		"different types": {
+			expected: `{"a": "10"}`,
+			actual:   `{"a": 10}`,
+
+			expectedDifferences: []string{`$.a: expected "10", got 10`},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			expected, _ := decodeJsonValue([]byte(testData.expected))
+			actual, _ := decodeJsonValue([]byte(testData.actual))
+
+			// Act
+			result := diffJson("$", expected, actual)
+
+			// Assert
+			assert.Equal(t, testData.expectedDifferences, result)
+		})
+	}
+}
+
+func TestResponseEqualJson_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	type item struct {
+		Price float64 `json:"price"`
+	}
+
+	tests := map[string]struct {
+		expected any
+		code     int
+		body     string
+
+		expectedOk     bool
+		expectedErrorf []string
+	}{
+		"equal struct": {
+			expected:   []item{{Price: 10}},
+			code:       http.StatusOK,
+			body:       `[{"price": 10.0}]`,
+			expectedOk: true,
+		},
+		"equal raw json": {
+			expected:   json.RawMessage(`{"b": 1, "a": 2}`),
+			code:       http.StatusOK,
+			body:       `{"a": 2, "b": 1}`,
+			expectedOk: true,
+		},
+		"different struct": {
+			expected:       []item{{Price: 10.5}},
+			code:           http.StatusOK,
+			body:           `[{"price": 10}]`,
+			expectedErrorf: []string{"Response body does not match the expected json:\n$[0].price: expected 10.5, got 10"},
+		},
+		"wrong status code": {
+			expected:       []item{},
+			code:           http.StatusCreated,
+			body:           `[]`,
+			expectedErrorf: []string{"Status code 200 is not 201"},
+		},
+		"invalid json": {
+			expected:       []item{},
+			code:           http.StatusOK,
+			body:           `[`,
+			expectedErrorf: []string{"Failed to unmarshall '[' as json: unexpected EOF"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			context.Data(http.StatusOK, binding.MIMEJSON, []byte(testData.body))
+
+			// Act
+			ok := ResponseEqualJson(testingObject, testData.expected, testData.code, writer.Result())
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestResponseEqualJson_LimitsDifferences(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.Data(http.StatusOK, binding.MIMEJSON, []byte(`[`+strings.Repeat(`1,`, 29)+`1]`))
+
+	// Act
+	ok := ResponseEqualJson(testingObject, make([]int, 30), http.StatusOK, writer.Result())
+
+	// Assert
+	assert.False(t, ok)
+
+	if assert.Len(t, testingObject.ErrorfCalls, 1) {
+		assert.True(t, strings.HasSuffix(testingObject.ErrorfCalls[0], "$[24]: expected 0, got 1\n... and 5 more"))
+	}
+}
@@ -66,6 +66,23 @@ func ExampleResponse() {
 	}
 }
 
+func ExampleResponseAs() {
+	// Arrange
+	t := new(mockT)
+	expected := []MyObject{{ID: 5}}
+	myController := &MyController{}
+
+	context, writer := PrepareRequest(t)
+
+	// Act
+	myController.Get(context)
+
+	// Assert
+	if result, ok := RecorderAs[[]MyObject](t, http.StatusOK, writer); ok {
+		assert.Equal(t, expected, result)
+	}
+}
+
 // without arguments expect called assumes that the endpoint is only called once and
 // creates a new expectation
 func ExampleExpectCalled_withoutVarargs() {
@@ -74,7 +91,7 @@ func ExampleExpectCalled_withoutVarargs() {
 	ginContext := gin.Default()
 
 	// create expectation
-	expectation := ExpectCalled(t, ginContext, "/hello-world")
+	expectation := ExpectCalled(t, ginContext, http.MethodGet, "/hello-world")
 
 	// create endpoints on ginContext
 	ginContext.GET("/hello-world", func(context *gin.Context) {
@@ -101,8 +118,8 @@ func ExampleExpectCalled_withVarargs() {
 	ginContext := gin.Default()
 
 	// create expectation
-	expectation := ExpectCalled(t, ginContext, "/hello-world", TimesCalled(2))
-	expectation = ExpectCalled(t, ginContext, "/other-path", Expectation(expectation))
+	expectation := ExpectCalled(t, ginContext, http.MethodGet, "/hello-world", TimesCalled(2))
+	expectation = ExpectCalled(t, ginContext, http.MethodGet, "/other-path", Expectation(expectation))
 
 	// create endpoints on ginContext
 	for _, endpoint := range []string{"/hello-world", "/other-path"} {
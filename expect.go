@@ -1,7 +1,11 @@
 package gintestutil
 
 import (
+	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,40 +13,166 @@ import (
 // ExpectOption allows various options to be supplied to Expect* functions
 type ExpectOption func(*calledConfig)
 
+// unbounded is used as the maximum of AtLeast, it's not -1 so that AtMost(-1) can be reported as invalid
+const unbounded = math.MinInt
+
 // TimesCalled is used to expect an invocation an X amount of times
 func TimesCalled(times int) ExpectOption {
 	return func(config *calledConfig) {
-		config.Times = times
+		config.Min = times
+		config.Max = times
+	}
+}
+
+// AtLeast is used to expect an invocation at least X amount of times
+func AtLeast(times int) ExpectOption {
+	return func(config *calledConfig) {
+		config.Min = times
+		config.Max = unbounded
+	}
+}
+
+// AtMost is used to expect an invocation at most X amount of times, including none at all
+func AtMost(times int) ExpectOption {
+	return func(config *calledConfig) {
+		config.Min = 0
+		config.Max = times
+	}
+}
+
+// Between is used to expect an invocation between min and max amount of times, inclusive
+func Between(min int, max int) ExpectOption {
+	return func(config *calledConfig) {
+		config.Min = min
+		config.Max = max
+	}
+}
+
+// VerifyOnCleanup verifies the minimum amount of calls when the test finishes, so under-calls are reported
+// without having to wait for the expectation. Calls above the maximum are always reported immediately.
+// This requires a TestingT with a Cleanup method, such as *testing.T.
+func VerifyOnCleanup() ExpectOption {
+	return func(config *calledConfig) {
+		config.VerifyOnCleanup = true
+	}
+}
+
+// MustBeRegistered makes ExpectCalled fail immediately if the route does not exist yet, which catches typos
+// in expectations on routers that were built before the test
+func MustBeRegistered() ExpectOption {
+	return func(config *calledConfig) {
+		config.MustBeRegistered = true
+	}
+}
+
+// Matching makes the expectation only count calls that match all matchers, other calls are ignored unless
+// ReportUnmatched is used
+func Matching(matchers ...RequestMatcher) ExpectOption {
+	return func(config *calledConfig) {
+		config.Matchers = append(config.Matchers, matchers...)
+	}
+}
+
+// ReportUnmatched reports calls to the endpoint that don't match the expectation as errors, instead of ignoring them
+func ReportUnmatched() ExpectOption {
+	return func(config *calledConfig) {
+		config.ReportUnmatched = true
 	}
 }
 
 // Expectation is used to have a global wait group to wait for
-// when asserting multiple calls made
-func Expectation(expectation *sync.WaitGroup) ExpectOption {
+// when asserting multiple calls made, this can be a *sync.WaitGroup or the result of an earlier ExpectCalled
+func Expectation(expectation Waiter) ExpectOption {
 	return func(config *calledConfig) {
-		config.Expectation = expectation
+		switch expectation := expectation.(type) {
+		case *sync.WaitGroup:
+			config.Expectation = expectation
+			config.group = &expectationGroup{external: true}
+
+		case *CallExpectation:
+			config.Expectation = expectation.waitGroup
+			config.group = expectation.group
+
+		default:
+			config.invalidExpectation = expectation
+		}
 	}
 }
 
 type calledConfig struct {
-	Times       int
-	Expectation *sync.WaitGroup
+	Min              int
+	Max              int
+	Expectation      *sync.WaitGroup
+	MustBeRegistered bool
+	VerifyOnCleanup  bool
+	Matchers         []RequestMatcher
+	ReportUnmatched  bool
+
+	// invalidExpectation is set when Expectation is given a waiter that can't be shared
+	invalidExpectation Waiter
+
+	// group contains the expectations that share the wait group, if it was shared through a *CallExpectation
+	group *expectationGroup
+}
+
+// matches checks whether the call satisfies all matchers
+func (c *calledConfig) matches(context *gin.Context) bool {
+	for _, matcher := range c.Matchers {
+		if !matcher(context) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe returns a human-readable version of the expected amount of calls
+func (c *calledConfig) describe() string {
+	switch {
+	case c.Max == 0:
+		return "never"
+
+	case c.Min == c.Max:
+		return fmt.Sprintf("%d times", c.Min)
+
+	case c.Max == unbounded:
+		return fmt.Sprintf("at least %d times", c.Min)
+
+	case c.Min == 0:
+		return fmt.Sprintf("at most %d times", c.Max)
+	}
+
+	return fmt.Sprintf("between %d and %d times", c.Min, c.Max)
+}
+
+// cleanupT is implemented by TestingT implementations that support VerifyOnCleanup
+type cleanupT interface {
+	Cleanup(func())
 }
 
 // ExpectCalled can be used on a gin endpoint to express an expectation that the endpoint will
 // be called some time in the future. In combination with a test
-// can wait for this expectation to be true or fail after some predetermined amount of time
-func ExpectCalled(t TestingT, ctx *gin.Engine, path string, options ...ExpectOption) *sync.WaitGroup {
+// can wait for this expectation to be true or fail after some predetermined amount of time.
+// The endpoint is identified by the method and the route as it was registered, such as /users/:id.
+//
+// The router can be a *gin.Engine or a *gin.RouterGroup, in which case the path is relative to the group. Both routes
+// that are already registered and routes that are registered afterwards can be expected. Because of the latter, an
+// unknown route is only reported when EnsureCompletion times out, use MustBeRegistered to report it immediately.
+//
+// The returned expectation records every matching call, which can be inspected with Calls.
+func ExpectCalled(t TestingT, router gin.IRoutes, method string, path string, options ...ExpectOption) *CallExpectation {
 	t.Helper()
 
-	if ctx == nil {
-		t.Errorf("context cannot be nil")
+	engine, path, err := resolveRouter(router, path)
+	if err != nil {
+		t.Errorf("%v", err)
 
 		return nil
 	}
 
 	config := &calledConfig{
-		Times:       1,
+		Min:         1,
+		Max:         1,
 		Expectation: &sync.WaitGroup{},
 	}
 
@@ -50,26 +180,126 @@ func ExpectCalled(t TestingT, ctx *gin.Engine, path string, options ...ExpectOpt
 		option(config)
 	}
 
-	// Set waitgroup for amount of times
-	config.Expectation.Add(config.Times)
+	if config.Min < 0 {
+		t.Errorf("%s %s can't be expected to be called a negative amount of %d times", method, path, config.Min)
+
+		return nil
+	}
+
+	if config.Max != unbounded && config.Max < config.Min {
+		t.Errorf("%s %s can't be expected to be called at most %d times and at least %d times", method, path, config.Max, config.Min)
+
+		return nil
+	}
+
+	if config.invalidExpectation != nil || config.Expectation == nil {
+		t.Errorf("%T cannot be used as an expectation, use a *sync.WaitGroup or *CallExpectation", config.invalidExpectation)
+
+		return nil
+	}
+
+	registered := routeRegistered(engine, method, path)
+	if !registered && config.MustBeRegistered {
+		t.Errorf("route %s %s is not registered, registered routes: %s", method, path, registeredRoutes(engine))
+
+		return nil
+	}
+
+	cleanup, canCleanup := t.(cleanupT)
+	if config.VerifyOnCleanup && !canCleanup {
+		t.Errorf("VerifyOnCleanup requires %T to have a Cleanup method", t)
+
+		return nil
+	}
+
+	// Set waitgroup for the minimum amount of times
+	config.Expectation.Add(config.Min)
+	if config.group == nil {
+		config.group = &expectationGroup{}
+	}
+
+	expectation := &CallExpectation{
+		waitGroup: config.Expectation,
+		group:     config.group,
+		engine:    engine,
+		method:    method,
+		path:      path,
+		min:       config.Min,
+		expected:  config.describe(),
+	}
+	config.group.add(expectation)
+
+	// Add middleware for provided route, requests are handled concurrently so the counter has to be atomic
+	var timesCalled int64
+	middleware := func(c *gin.Context) {
+		if c.Request.Method != method || c.FullPath() != path {
+			c.Next()
 
-	// Add middleware for provided route
-	var timesCalled int
-	ctx.Use(func(c *gin.Context) {
-		c.Next()
-		if c.FullPath() != path {
 			return
 		}
 
-		timesCalled++
-		if timesCalled <= config.Times {
-			config.Expectation.Done()
+		// Matchers run and the body is captured before the handler, which might consume the body
+		matched := config.matches(c)
+		body := requestBody(c)
+		sequence := atomic.AddUint64(&callSequence, 1)
+		start := time.Now()
+		c.Next()
+
+		if !matched {
+			if config.ReportUnmatched {
+				t.Errorf("%s %s received a call that does not match the expectation: %s\n", method, path, c.Request.URL)
+			}
 
 			return
 		}
 
-		t.Errorf("%s hook asserts called %d times but called at least %d times\n", path, config.Times, timesCalled)
-	})
+		requestUrl := *c.Request.URL
+		expectation.record(Call{
+			Method:   c.Request.Method,
+			Url:      &requestUrl,
+			Header:   c.Request.Header.Clone(),
+			Body:     body,
+			Params:   append(gin.Params(nil), c.Params...),
+			Status:   c.Writer.Status(),
+			Duration: time.Since(start),
+			Sequence: sequence,
+		})
+
+		called := atomic.AddInt64(&timesCalled, 1)
+		if called <= int64(config.Min) {
+			expectation.satisfy()
+		}
+
+		if config.Max != unbounded && called > int64(config.Max) {
+			t.Errorf("%s %s hook asserts called %s but called at least %d times\n", method, path, config.describe(), called)
+		}
+	}
+
+	if config.VerifyOnCleanup {
+		cleanup.Cleanup(func() {
+			if called := atomic.LoadInt64(&timesCalled); called < int64(config.Min) {
+				t.Errorf("%s %s hook asserts called %s but called %d times\n", method, path, config.describe(), called)
+			}
+		})
+	}
+
+	if !registered {
+		router.Use(middleware)
+
+		return expectation
+	}
+
+	if err := prependToRoute(engine, method, path, middleware); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	return expectation
+}
+
+// ExpectNotCalled expresses the expectation that an endpoint is never called, every call is reported immediately.
+// It accepts the same options as ExpectCalled, except that the amount of calls is always zero.
+func ExpectNotCalled(t TestingT, router gin.IRoutes, method string, path string, options ...ExpectOption) *CallExpectation {
+	t.Helper()
 
-	return config.Expectation
+	return ExpectCalled(t, router, method, path, append(options, TimesCalled(0))...)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,7 +21,7 @@ func TestExpectCalled_SingleCallReturnsSuccess(t *testing.T) {
 	ginContext := gin.Default()
 
 	// Act
-	expectation := ExpectCalled(testObject, ginContext, "/ping")
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/ping")
 
 	// Assert
 	ginContext.GET("/ping", func(ctx *gin.Context) {
@@ -55,7 +56,7 @@ func TestExpectCalled_ZeroCallsFails(t *testing.T) {
 	ginContext := gin.Default()
 
 	// Act
-	expectation := ExpectCalled(testObject, ginContext, "/ping")
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/ping")
 
 	// Assert
 	ginContext.GET("/ping", func(ctx *gin.Context) {
@@ -92,7 +93,7 @@ func TestExpectCalled_NilGinContextReturnsError(t *testing.T) {
 	var ginContext *gin.Engine
 
 	// Act
-	expectation := ExpectCalled(testObject, ginContext, "")
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "")
 
 	// Assert
 	assert.True(t, testObject.Failed())
@@ -107,7 +108,7 @@ func TestExpectCalled_CalledTooOftenReturnsError(t *testing.T) {
 	ginContext := gin.Default()
 
 	// Act
-	expectation := ExpectCalled(testObject, ginContext, "/ping")
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/ping")
 
 	// Assert
 	ginContext.GET("/ping", func(ctx *gin.Context) {
@@ -142,8 +143,8 @@ func TestExpectCalled_TwoTimesWithTwoEndpointsSucceeds(t *testing.T) {
 	ginContext := gin.Default()
 
 	// Act
-	expectation := ExpectCalled(testObject, ginContext, "/ping", TimesCalled(2))
-	expectation = ExpectCalled(testObject, ginContext, "/pong", Expectation(expectation))
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/ping", TimesCalled(2))
+	expectation = ExpectCalled(testObject, ginContext, http.MethodGet, "/pong", Expectation(expectation))
 
 	// Assert
 	for _, endpoint := range []string{"/ping", "/pong"} {
@@ -172,3 +173,452 @@ func TestExpectCalled_TwoTimesWithTwoEndpointsSucceeds(t *testing.T) {
 		t.Error("did not complete")
 	}
 }
+
+func TestExpectCalled_OtherMethodIsNotCounted(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(testing.T)
+	ginContext := gin.New()
+
+	expectation := ExpectCalled(testObject, ginContext, http.MethodDelete, "/orders/:id")
+
+	ginContext.GET("/orders/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	ginContext.DELETE("/orders/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	ts := httptest.NewServer(ginContext)
+	defer ts.Close()
+
+	// Act
+	_, _ = http.Get(fmt.Sprintf("%s%s", ts.URL, "/orders/5"))
+
+	completionChannel := make(chan struct{})
+
+	go func() {
+		expectation.Wait()
+		close(completionChannel)
+	}()
+
+	// Assert
+	select {
+	case <-completionChannel:
+		t.Error("should not have completed")
+
+	case <-time.After(time.Second):
+		// Success!
+	}
+
+	request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s%s", ts.URL, "/orders/5"), nil)
+	_, _ = http.DefaultClient.Do(request)
+
+	select {
+	case <-completionChannel:
+		assert.False(t, testObject.Failed())
+
+	case <-time.After(expectTimeout):
+		t.Error("did not complete")
+	}
+}
+
+func TestExpectCalled_ConcurrentCallsAreCounted(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	const calls = 50
+
+	testObject := new(testing.T)
+	ginContext := gin.New()
+
+	expectation := ExpectCalled(testObject, ginContext, http.MethodPost, "/orders", TimesCalled(calls))
+
+	ginContext.POST("/orders", func(ctx *gin.Context) {
+		ctx.Status(http.StatusCreated)
+	})
+
+	// Act
+	var requests sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		requests.Add(1)
+
+		go func() {
+			defer requests.Done()
+
+			context, _ := PrepareRequest(t, WithMethod(http.MethodPost), WithUrl("/orders"))
+			ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+		}()
+	}
+
+	requests.Wait()
+
+	// Assert
+	assert.True(t, EnsureCompletion(testObject, expectation, WithTimeout(expectTimeout)))
+	assert.False(t, testObject.Failed())
+}
+
+func TestExpectCalled_AlreadyRegisteredRoutes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		router func(*gin.Engine) gin.IRoutes
+		path   string
+	}{
+		"engine": {
+			router: func(engine *gin.Engine) gin.IRoutes { return engine },
+			path:   "/api/users/:id",
+		},
+		"group": {
+			router: func(engine *gin.Engine) gin.IRoutes { return engine.Group("/api") },
+			path:   "/users/:id",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			ginContext := gin.New()
+			ginContext.Group("/api").GET("/users/:id", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			expectation := ExpectCalled(testObject, testData.router(ginContext), http.MethodGet, testData.path, MustBeRegistered())
+
+			// Act
+			context, _ := PrepareRequest(t, WithUrl("/api/users/5"))
+			ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+
+			// Assert
+			assert.True(t, EnsureCompletion(new(testing.T), expectation, WithTimeout(expectTimeout)))
+			assert.Empty(t, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestExpectCalled_MustBeRegisteredFailsOnUnknownRoute(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	ginContext := gin.New()
+	ginContext.GET("/users", func(ctx *gin.Context) {})
+	ginContext.POST("/users", func(ctx *gin.Context) {})
+
+	// Act
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/user", MustBeRegistered())
+
+	// Assert
+	assert.Nil(t, expectation)
+	assert.Equal(t, []string{"route GET /user is not registered, registered routes: [GET /users, POST /users]"}, testObject.ErrorfCalls)
+}
+
+func TestExpectCalled_UnknownRouteIsReportedOnTimeout(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	ginContext := gin.New()
+	ginContext.GET("/users", func(ctx *gin.Context) {})
+
+	expectation := ExpectCalled(testObject, ginContext, http.MethodGet, "/user")
+
+	context, _ := PrepareRequest(t, WithUrl("/users"))
+	ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+
+	// Act
+	ok := EnsureCompletion(testObject, expectation, WithTimeout(50*time.Millisecond))
+
+	// Assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"tasks did not complete within: 50ms, unmet expectations:\n" +
+			"GET /user: expected 1 times, called 0 times, the route is not registered, registered routes: [GET /users]",
+	}, testObject.ErrorfCalls)
+}
+
+func TestCalledConfig_DescribesExpectedCalls(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		option ExpectOption
+
+		expected string
+	}{
+		"times called": {
+			option:   TimesCalled(2),
+			expected: "2 times",
+		},
+		"never": {
+			option:   TimesCalled(0),
+			expected: "never",
+		},
+		"at least": {
+			option:   AtLeast(1),
+			expected: "at least 1 times",
+		},
+		"at most": {
+			option:   AtMost(3),
+			expected: "at most 3 times",
+		},
+		"between": {
+			option:   Between(1, 3),
+			expected: "between 1 and 3 times",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			config := &calledConfig{}
+
+			// Act
+			testData.option(config)
+
+			// Assert
+			assert.Equal(t, testData.expected, config.describe())
+		})
+	}
+}
+
+func TestExpectCalled_CallCountRanges(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		option ExpectOption
+		calls  int
+
+		expectedErrorf []string
+	}{
+		"at least with fewer calls": {
+			option:         AtLeast(2),
+			calls:          1,
+			expectedErrorf: []string{"GET /ping hook asserts called at least 2 times but called 1 times\n"},
+		},
+		"at least with more calls": {
+			option: AtLeast(2),
+			calls:  4,
+		},
+		"at most without calls": {
+			option: AtMost(2),
+			calls:  0,
+		},
+		"at most with more calls": {
+			option:         AtMost(1),
+			calls:          2,
+			expectedErrorf: []string{"GET /ping hook asserts called at most 1 times but called at least 2 times\n"},
+		},
+		"between with fewer calls": {
+			option:         Between(2, 3),
+			calls:          1,
+			expectedErrorf: []string{"GET /ping hook asserts called between 2 and 3 times but called 1 times\n"},
+		},
+		"between with calls in range": {
+			option: Between(1, 3),
+			calls:  3,
+		},
+		"between with more calls": {
+			option:         Between(1, 3),
+			calls:          4,
+			expectedErrorf: []string{"GET /ping hook asserts called between 1 and 3 times but called at least 4 times\n"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			ginContext := gin.New()
+			ginContext.GET("/ping", func(ctx *gin.Context) {})
+
+			_ = ExpectCalled(testObject, ginContext, http.MethodGet, "/ping", testData.option, VerifyOnCleanup())
+
+			// Act
+			for i := 0; i < testData.calls; i++ {
+				ginContext.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+			}
+
+			for _, cleanup := range testObject.Cleanups {
+				cleanup()
+			}
+
+			// Assert
+			assert.Equal(t, testData.expectedErrorf, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestExpectCalled_FailsOnInvalidBounds(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		option ExpectOption
+
+		expectedErrorf string
+	}{
+		"negative times": {
+			option:         TimesCalled(-1),
+			expectedErrorf: "GET /ping can't be expected to be called a negative amount of -1 times",
+		},
+		"negative at least": {
+			option:         AtLeast(-1),
+			expectedErrorf: "GET /ping can't be expected to be called a negative amount of -1 times",
+		},
+		"negative at most": {
+			option:         AtMost(-1),
+			expectedErrorf: "GET /ping can't be expected to be called at most -1 times and at least 0 times",
+		},
+		"negative between": {
+			option:         Between(-1, 2),
+			expectedErrorf: "GET /ping can't be expected to be called a negative amount of -1 times",
+		},
+		"reversed between": {
+			option:         Between(3, 1),
+			expectedErrorf: "GET /ping can't be expected to be called at most 1 times and at least 3 times",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+
+			// Act
+			result := ExpectCalled(testObject, gin.New(), http.MethodGet, "/ping", testData.option)
+
+			// Assert
+			assert.Nil(t, result)
+			assert.Equal(t, []string{testData.expectedErrorf}, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestExpectNotCalled_ReportsCalls(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	ginContext := gin.New()
+	ginContext.GET("/ping", func(ctx *gin.Context) {})
+
+	expectation := ExpectNotCalled(testObject, ginContext, http.MethodGet, "/ping")
+
+	// Act
+	ginContext.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	// Assert
+	assert.True(t, EnsureCompletion(new(testing.T), expectation, WithTimeout(expectTimeout)))
+	assert.Equal(t, []string{"GET /ping hook asserts called never but called at least 1 times\n"}, testObject.ErrorfCalls)
+}
+
+// noCleanupT is a TestingT without a Cleanup method
+type noCleanupT struct {
+	TestingT
+}
+
+func TestExpectCalled_VerifyOnCleanupRequiresCleanup(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	expectation := ExpectCalled(noCleanupT{testObject}, gin.New(), http.MethodGet, "/ping", VerifyOnCleanup())
+
+	// Assert
+	assert.Nil(t, expectation)
+	assert.Equal(t, []string{"VerifyOnCleanup requires gintestutil.noCleanupT to have a Cleanup method"}, testObject.ErrorfCalls)
+}
+
+func TestExpectCalled_RecordsMatchingCalls(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	ginContext := gin.New()
+	ginContext.PUT("/users/:id", func(ctx *gin.Context) {
+		_, _ = ctx.GetRawData()
+		ctx.Status(http.StatusAccepted)
+	})
+
+	expectation := ExpectCalled(testObject, ginContext, http.MethodPut, "/users/:id", Matching(MatchUrlParam("id", "5")))
+
+	// Act
+	for _, id := range []string{"4", "5"} {
+		context, _ := PrepareRequest(t,
+			WithMethod(http.MethodPut),
+			WithRoute("/users/:id", map[string]any{"id": id}),
+			WithQueryParams(map[string]any{"dry": "true"}),
+			WithHeaders(http.Header{"X-Tenant": []string{"ing"}}),
+			WithJsonBody(t, testStruct{Name: "John"}),
+		)
+
+		ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+	}
+
+	// Assert
+	assert.True(t, EnsureCompletion(new(testing.T), expectation, WithTimeout(expectTimeout)))
+	assert.Empty(t, testObject.ErrorfCalls)
+
+	calls := expectation.Calls()
+	if assert.Len(t, calls, 1) {
+		call := calls[0]
+		assert.Equal(t, http.MethodPut, call.Method)
+		assert.Equal(t, "https://example.com/users/5?dry=true", call.Url.String())
+		assert.Equal(t, "ing", call.Header.Get("X-Tenant"))
+		assert.Equal(t, `{"name":"John"}`, string(call.Body))
+		assert.Equal(t, gin.Params{{Key: "id", Value: "5"}}, call.Params)
+		assert.Equal(t, http.StatusAccepted, call.Status)
+		assert.GreaterOrEqual(t, call.Duration, time.Duration(0))
+
+		var result testStruct
+		assert.True(t, call.Decode(testObject, &result))
+		assert.Equal(t, testStruct{Name: "John"}, result)
+	}
+}
+
+func TestCall_DecodeFailsOnInvalidBody(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		call Call
+
+		expectedErrorf string
+	}{
+		"invalid json": {
+			call:           Call{Header: http.Header{}, Body: []byte(`{`)},
+			expectedErrorf: "Failed to unmarshall '{' into '*gintestutil.testStruct': unexpected end of JSON input",
+		},
+		"unknown content type": {
+			call:           Call{Header: http.Header{"Content-Type": []string{"application/unknown"}}, Body: []byte(`{}`)},
+			expectedErrorf: "No decoder registered for Content-Type application/unknown",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+
+			// Act
+			ok := testData.call.Decode(testObject, &testStruct{})
+
+			// Assert
+			assert.False(t, ok)
+			assert.Equal(t, []string{testData.expectedErrorf}, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestExpectCalled_FailsOnUnsupportedExpectation(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	result := ExpectCalled(testObject, gin.New(), http.MethodGet, "/ping", Expectation(sync.NewCond(new(sync.Mutex))))
+
+	// Assert
+	assert.Nil(t, result)
+	assert.Equal(t, []string{"*sync.Cond cannot be used as an expectation, use a *sync.WaitGroup or *CallExpectation"}, testObject.ErrorfCalls)
+}
@@ -0,0 +1,296 @@
+package gintestutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// FakeServer is a gin webserver that stands in for an external API, its endpoints are stubbed with On
+// and the expectations of every stub are verified when the test finishes
+type FakeServer struct {
+	t      TestingT
+	engine *gin.Engine
+	server *httptest.Server
+
+	mutex sync.RWMutex
+	stubs map[string][]*Stub
+}
+
+// NewFakeServer starts a fake server that is closed when the test finishes. This requires a TestingT with a
+// Cleanup method, such as *testing.T.
+func NewFakeServer(t TestingT) *FakeServer {
+	t.Helper()
+
+	cleanup, ok := t.(cleanupT)
+	if !ok {
+		t.Errorf("NewFakeServer requires %T to have a Cleanup method", t)
+
+		return nil
+	}
+
+	fake := &FakeServer{
+		t:      t,
+		engine: gin.New(),
+		stubs:  map[string][]*Stub{},
+	}
+
+	fake.engine.NoRoute(func(c *gin.Context) {
+		t.Errorf("fake server received an unexpected call: %s %s", c.Request.Method, c.Request.URL)
+		c.Status(http.StatusNotFound)
+	})
+
+	fake.server = httptest.NewServer(fake.engine)
+	cleanup.Cleanup(fake.server.Close)
+
+	return fake
+}
+
+// Url returns the base url of the fake server, such as http://127.0.0.1:1234
+func (f *FakeServer) Url() string {
+	return f.server.URL
+}
+
+// On stubs an endpoint of the fake server, identified by the method and the route such as /users/:id. By default
+// the stub is expected to be called at least once, which can be changed with options like TimesCalled. Stubs on the
+// same route are tried in the order in which they were created, the first one whose matchers match handles the call.
+// If the route can't be registered or the options are invalid, the error is reported and nil is returned.
+func (f *FakeServer) On(method string, path string, options ...ExpectOption) *Stub {
+	f.t.Helper()
+
+	stub := &Stub{server: f}
+	key := fmt.Sprintf("%s %s", method, path)
+
+	f.mutex.Lock()
+	_, registered := f.stubs[key]
+	f.stubs[key] = append(f.stubs[key], stub)
+	f.mutex.Unlock()
+
+	if !registered && !registerRoute(f.t, f.engine, method, path, f.handle(key)) {
+		f.removeStub(key, stub, false)
+
+		return nil
+	}
+
+	selected := func(c *gin.Context) bool {
+		return f.selectStub(key, c) == stub
+	}
+
+	defaults := []ExpectOption{AtLeast(1), VerifyOnCleanup(), Matching(selected)}
+	stub.CallExpectation = ExpectCalled(f.t, f.engine, method, path, append(defaults, options...)...)
+	if stub.CallExpectation == nil {
+		f.removeStub(key, stub, true)
+
+		return nil
+	}
+
+	return stub
+}
+
+// removeStub removes a stub that couldn't be set up, the route is forgotten if it isn't registered on the engine
+func (f *FakeServer) removeStub(key string, stub *Stub, routeRegistered bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stubs := f.stubs[key][:0]
+	for _, other := range f.stubs[key] {
+		if other != stub {
+			stubs = append(stubs, other)
+		}
+	}
+
+	if len(stubs) == 0 && !routeRegistered {
+		delete(f.stubs, key)
+
+		return
+	}
+
+	f.stubs[key] = stubs
+}
+
+// selectStub returns the first stub of the route that matches the call
+func (f *FakeServer) selectStub(key string, c *gin.Context) *Stub {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for _, stub := range f.stubs[key] {
+		if stub.matches(c) {
+			return stub
+		}
+	}
+
+	return nil
+}
+
+// handle serves the responses of the stubs of a route
+func (f *FakeServer) handle(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stub := f.selectStub(key, c)
+		if stub == nil {
+			f.t.Errorf("fake server received a call that matches none of the stubs of %s: %s", key, c.Request.URL)
+			c.Status(http.StatusNotImplemented)
+
+			return
+		}
+
+		response := stub.next()
+
+		if response.delay > 0 {
+			select {
+			case <-time.After(response.delay):
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+
+		if response.reset {
+			f.resetConnection(c)
+
+			return
+		}
+
+		if response.body == nil {
+			c.Status(response.status)
+
+			return
+		}
+
+		c.Data(response.status, response.contentType, response.body)
+	}
+}
+
+// resetConnection closes the connection without a response, a linger of 0 makes the client receive a reset
+func (f *FakeServer) resetConnection(c *gin.Context) {
+	connection, _, err := c.Writer.Hijack()
+	if err != nil {
+		f.t.Errorf("failed to reset connection: %v", err)
+		c.Status(http.StatusInternalServerError)
+
+		return
+	}
+
+	if tcpConnection, ok := connection.(*net.TCPConn); ok {
+		_ = tcpConnection.SetLinger(0)
+	}
+
+	_ = connection.Close()
+}
+
+// stubResponse is a canned response of a stub
+type stubResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	delay       time.Duration
+	reset       bool
+}
+
+// Stub is a stubbed endpoint of a FakeServer, its responses are served in the order in which they were added
+// and the last one is repeated for every call after that. Without responses, calls are answered with 200 OK.
+// The embedded CallExpectation records the calls that were handled by the stub.
+type Stub struct {
+	*CallExpectation
+
+	server    *FakeServer
+	matchers  []RequestMatcher
+	responses []*stubResponse
+	served    int
+}
+
+// Match makes the stub only handle calls that match all matchers
+func (s *Stub) Match(matchers ...RequestMatcher) *Stub {
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	s.matchers = append(s.matchers, matchers...)
+
+	return s
+}
+
+// Respond adds a response with the given status code, the body is sent with the content type if it's not nil
+func (s *Stub) Respond(code int, contentType string, body []byte) *Stub {
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	s.responses = append(s.responses, &stubResponse{status: code, contentType: contentType, body: body})
+
+	return s
+}
+
+// RespondJson adds a response with the given status code and the body marshalled to json
+func (s *Stub) RespondJson(code int, body any) *Stub {
+	s.server.t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		s.server.t.Errorf("failed to marshal response of stub: %v", err)
+
+		return s
+	}
+
+	return s.Respond(code, binding.MIMEJSON, data)
+}
+
+// ResetConnection adds a response that closes the connection without responding, to simulate network failures
+func (s *Stub) ResetConnection() *Stub {
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	s.responses = append(s.responses, &stubResponse{reset: true})
+
+	return s
+}
+
+// Delay delays the last added response by the given duration, to simulate slow servers
+func (s *Stub) Delay(delay time.Duration) *Stub {
+	s.server.t.Helper()
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	if len(s.responses) == 0 {
+		s.server.t.Error("Delay must be preceded by a response")
+
+		return s
+	}
+
+	s.responses[len(s.responses)-1].delay = delay
+
+	return s
+}
+
+// matches checks whether the call satisfies all matchers, the caller must hold the lock of the server
+func (s *Stub) matches(c *gin.Context) bool {
+	for _, matcher := range s.matchers {
+		if !matcher(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// next returns the response for the next call
+func (s *Stub) next() stubResponse {
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	if len(s.responses) == 0 {
+		return stubResponse{status: http.StatusOK}
+	}
+
+	index := s.served
+	if index >= len(s.responses) {
+		index = len(s.responses) - 1
+	}
+
+	s.served++
+
+	return *s.responses[index]
+}
@@ -0,0 +1,247 @@
+package gintestutil
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runCleanups runs the cleanups of the mock in reverse order, like testing.T does
+func runCleanups(testObject *mockT) {
+	for index := len(testObject.Cleanups) - 1; index >= 0; index-- {
+		testObject.Cleanups[index]()
+	}
+}
+
+// get calls the url and returns the status code and body of the response
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	response, err := http.Get(url)
+	if !assert.NoError(t, err) {
+		return 0, ""
+	}
+
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+
+	return response.StatusCode, string(body)
+}
+
+func TestFakeServer_ServesSequencedResponses(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	stub := fake.On(http.MethodGet, "/users/:id").
+		Respond(http.StatusServiceUnavailable, "", nil).
+		RespondJson(http.StatusOK, testStruct{Name: "John"})
+
+	// Act
+	var statuses []int
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		status, body := get(t, fake.Url()+"/users/5")
+		statuses = append(statuses, status)
+		bodies = append(bodies, body)
+	}
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}, statuses)
+	assert.Equal(t, []string{"", `{"name":"John"}`, `{"name":"John"}`}, bodies)
+	assert.Len(t, stub.Calls(), 3)
+	assert.Empty(t, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_SelectsStubByMatchers(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	admin := fake.On(http.MethodGet, "/users/:id", TimesCalled(1)).
+		Match(MatchUrlParam("id", "1")).
+		RespondJson(http.StatusOK, testStruct{Name: "admin"})
+
+	other := fake.On(http.MethodGet, "/users/:id", TimesCalled(2)).
+		RespondJson(http.StatusNotFound, nil)
+
+	// Act
+	adminStatus, adminBody := get(t, fake.Url()+"/users/1")
+	otherStatus, _ := get(t, fake.Url()+"/users/2")
+	_, _ = get(t, fake.Url()+"/users/3")
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, adminStatus)
+	assert.Equal(t, `{"name":"admin"}`, adminBody)
+	assert.Equal(t, http.StatusNotFound, otherStatus)
+	assert.Len(t, admin.Calls(), 1)
+	assert.Len(t, other.Calls(), 2)
+	assert.Empty(t, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_ReportsUnexpectedAndMissingCalls(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	fake.On(http.MethodGet, "/users/:id").Match(MatchUrlParam("id", "1"))
+	fake.On(http.MethodPost, "/users")
+
+	// Act
+	unknownStatus, _ := get(t, fake.Url()+"/unknown")
+	unmatchedStatus, _ := get(t, fake.Url()+"/users/2")
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, unknownStatus)
+	assert.Equal(t, http.StatusNotImplemented, unmatchedStatus)
+	assert.Equal(t, []string{
+		"fake server received an unexpected call: GET /unknown",
+		"fake server received a call that matches none of the stubs of GET /users/:id: /users/2",
+		"POST /users hook asserts called at least 1 times but called 0 times\n",
+		"GET /users/:id hook asserts called at least 1 times but called 0 times\n",
+	}, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_DelaysResponse(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	fake.On(http.MethodGet, "/slow").Respond(http.StatusOK, "", nil).Delay(100 * time.Millisecond)
+
+	// Act
+	start := time.Now()
+	status, _ := get(t, fake.Url()+"/slow")
+	elapsed := time.Since(start)
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, status)
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+	assert.Empty(t, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_ResetsConnection(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	fake.On(http.MethodGet, "/flaky").ResetConnection()
+
+	// Act
+	response, err := http.Get(fake.Url() + "/flaky")
+	if err == nil {
+		_ = response.Body.Close()
+	}
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Empty(t, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_DelayWithoutResponseFails(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	// Act
+	fake.On(http.MethodGet, "/slow", AtLeast(0)).Delay(time.Second)
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, []any{"Delay must be preceded by a response"}, testObject.ErrorCalls)
+}
+
+func TestNewFakeServer_RequiresCleanup(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	result := NewFakeServer(struct{ TestingT }{testObject})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.Equal(t, []string{"NewFakeServer requires struct { gintestutil.TestingT } to have a Cleanup method"}, testObject.ErrorfCalls)
+}
+
+func TestFakeServer_On_ReturnsNilOnError(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		path    string
+		options []ExpectOption
+
+		expectedErrorf []string
+	}{
+		"invalid route": {
+			path:           "/users/*all/posts",
+			expectedErrorf: []string{"failed to register route /users/*all/posts: catch-all routes are only allowed at the end of the path in path '/users/*all/posts'"},
+		},
+		"invalid options": {
+			path:           "/users",
+			options:        []ExpectOption{Between(2, 1)},
+			expectedErrorf: []string{"GET /users can't be expected to be called at most 1 times and at least 2 times"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			fake := NewFakeServer(testObject)
+
+			// Act
+			result := fake.On(http.MethodGet, testData.path, testData.options...)
+
+			runCleanups(testObject)
+
+			// Assert
+			assert.Nil(t, result)
+			assert.Empty(t, fake.stubs[http.MethodGet+" "+testData.path])
+			assert.Equal(t, testData.expectedErrorf, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestFakeServer_On_AllowsStubAfterInvalidOptions(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+	fake := NewFakeServer(testObject)
+
+	fake.On(http.MethodGet, "/users", Between(2, 1))
+	stub := fake.On(http.MethodGet, "/users").RespondJson(http.StatusOK, testStruct{Name: "John"})
+
+	// Act
+	status, body := get(t, fake.Url()+"/users")
+
+	runCleanups(testObject)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"name":"John"}`, body)
+	assert.Len(t, stub.Calls(), 1)
+	assert.Equal(t, []string{"GET /users can't be expected to be called at most 1 times and at least 2 times"}, testObject.ErrorfCalls)
+}
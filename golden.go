@@ -0,0 +1,277 @@
+package gintestutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// UpdateGoldenEnv is the environment variable that makes AssertGolden write golden files instead of comparing
+	// them, a -update flag defined by the test binary has the same effect.
+	UpdateGoldenEnv = "GINTESTUTIL_UPDATE_GOLDEN"
+
+	// redactedValue replaces redacted values in golden files
+	redactedValue = "<redacted>"
+
+	// goldenDirPermissions and goldenFilePermissions are used when writing golden files
+	goldenDirPermissions  = 0o755
+	goldenFilePermissions = 0o644
+)
+
+// jsonPathPattern splits a path like $.items[*].id or $["my key"] into its segments
+var jsonPathPattern = regexp.MustCompile(`\.([^.\[]+)|\[(\*|\d+)\]|\["([^"]*)"\]`)
+
+// GoldenOption allows various options to be supplied to AssertGolden
+type GoldenOption func(*goldenConfig)
+
+// GoldenDir sets the directory of the golden files, defaults to testdata
+func GoldenDir(dir string) GoldenOption {
+	return func(config *goldenConfig) {
+		config.dir = dir
+	}
+}
+
+// GoldenHeaders adds response headers to the golden file, by default only the status and body are stored
+func GoldenHeaders(headers ...string) GoldenOption {
+	return func(config *goldenConfig) {
+		config.headers = append(config.headers, headers...)
+	}
+}
+
+// RedactPath replaces json values at the given paths with <redacted>, use it for volatile fields such as timestamps.
+// Paths look like $.createdAt, $.items[0].id, $.items[*].id or $["trace-id"].
+func RedactPath(paths ...string) GoldenOption {
+	return func(config *goldenConfig) {
+		config.paths = append(config.paths, paths...)
+	}
+}
+
+// RedactPattern replaces all matches of the pattern in headers and body with <redacted>, use it for values like
+// UUIDs that may appear anywhere
+func RedactPattern(patterns ...*regexp.Regexp) GoldenOption {
+	return func(config *goldenConfig) {
+		config.patterns = append(config.patterns, patterns...)
+	}
+}
+
+type goldenConfig struct {
+	dir      string
+	headers  []string
+	paths    []string
+	patterns []*regexp.Regexp
+}
+
+// AssertGolden compares the normalised status, selected headers and body of the response with the golden file
+// testdata/<name>.golden. Json bodies are pretty-printed with sorted keys, so they are stable and easy to review. Set
+// GINTESTUTIL_UPDATE_GOLDEN=1 or define and pass an -update flag to (re)generate the golden files.
+func AssertGolden(t TestingT, name string, res *http.Response, options ...GoldenOption) bool {
+	t.Helper()
+
+	config := &goldenConfig{
+		dir: "testdata",
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Errorf("failed to read body of response")
+
+		return false
+	}
+
+	actual, err := renderGolden(res, body, config)
+	if err != nil {
+		t.Error(err)
+
+		return false
+	}
+
+	path := filepath.Join(config.dir, filepath.FromSlash(name)+".golden")
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), goldenDirPermissions); err != nil {
+			t.Error(err)
+
+			return false
+		}
+
+		if err := os.WriteFile(path, []byte(actual), goldenFilePermissions); err != nil {
+			t.Error(err)
+
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Golden file %s does not exist, set %s=1 to create it", path, UpdateGoldenEnv)
+
+		return false
+	}
+
+	if err != nil {
+		t.Error(err)
+
+		return false
+	}
+
+	if string(expected) == actual {
+		return true
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(actual),
+		FromFile: path,
+		ToFile:   "response",
+		Context:  2,
+	})
+
+	t.Errorf("Response does not match golden file, set %s=1 to update it:\n%s", UpdateGoldenEnv, diff)
+
+	return false
+}
+
+// shouldUpdateGolden checks the environment variable and the -update flag, the flag is only looked up because
+// defining it here would conflict with test binaries that already define it
+func shouldUpdateGolden() bool {
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); update {
+		return true
+	}
+
+	if updateFlag := flag.Lookup("update"); updateFlag != nil {
+		update, _ := strconv.ParseBool(updateFlag.Value.String())
+
+		return update
+	}
+
+	return false
+}
+
+// renderGolden creates the normalised contents of a golden file
+func renderGolden(res *http.Response, body []byte, config *goldenConfig) (string, error) {
+	builder := new(strings.Builder)
+	_, _ = fmt.Fprintf(builder, "HTTP %d\n", res.StatusCode)
+
+	for _, header := range config.headers {
+		for _, value := range res.Header.Values(header) {
+			_, _ = fmt.Fprintf(builder, "%s: %s\n", http.CanonicalHeaderKey(header), value)
+		}
+	}
+
+	if statusHasBody(res.StatusCode) && len(body) > 0 {
+		normalised, err := normaliseGoldenBody(body, config.paths)
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = fmt.Fprintf(builder, "\n%s\n", normalised)
+	}
+
+	result := builder.String()
+	for _, pattern := range config.patterns {
+		result = pattern.ReplaceAllString(result, redactedValue)
+	}
+
+	return result, nil
+}
+
+// normaliseGoldenBody pretty-prints json bodies and redacts the given paths, other bodies are returned as-is
+func normaliseGoldenBody(body []byte, paths []string) (string, error) {
+	value, err := decodeJsonValue(body)
+	if err != nil {
+		if len(paths) > 0 {
+			return "", fmt.Errorf("cannot redact paths %v in a body that is not json: %w", paths, err)
+		}
+
+		return string(body), nil
+	}
+
+	for _, path := range paths {
+		segments, err := parseJsonPath(path)
+		if err != nil {
+			return "", err
+		}
+
+		value = redactJsonPath(value, segments)
+	}
+
+	result := new(bytes.Buffer)
+	encoder := json.NewEncoder(result)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(result.String(), "\n"), nil
+}
+
+// parseJsonPath turns a path like $.items[*].id into the segments "items", "*" and "id"
+func parseJsonPath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %s must start with $", path)
+	}
+
+	rest := strings.TrimPrefix(path, "$")
+
+	var segments []string
+
+	for rest != "" {
+		match := jsonPathPattern.FindStringSubmatch(rest)
+		if match == nil || !strings.HasPrefix(rest, match[0]) {
+			return nil, fmt.Errorf("invalid json path %s at '%s'", path, rest)
+		}
+
+		segments = append(segments, match[1]+match[2]+match[3])
+		rest = rest[len(match[0]):]
+	}
+
+	return segments, nil
+}
+
+// redactJsonPath replaces the value at the path with <redacted>, * matches all items of an array or object
+func redactJsonPath(value any, segments []string) any {
+	if len(segments) == 0 {
+		return redactedValue
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, item := range typedValue {
+			if segment == "*" || segment == key {
+				typedValue[key] = redactJsonPath(item, rest)
+			}
+		}
+
+	case []any:
+		for index, item := range typedValue {
+			if segment == "*" || segment == strconv.Itoa(index) {
+				typedValue[index] = redactJsonPath(item, rest)
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,213 @@
+package gintestutil
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderGolden_ReturnsNormalisedResponse(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		render  func(*gin.Context)
+		options []GoldenOption
+
+		expected string
+	}{
+		"json body is pretty-printed with sorted keys": {
+			render: func(c *gin.Context) {
+				c.Data(http.StatusOK, binding.MIMEJSON, []byte(`{"b": 1.50, "a": ["<x>"]}`))
+			},
+
+			expected: "HTTP 200\n\n{\n  \"a\": [\n    \"<x>\"\n  ],\n  \"b\": 1.50\n}\n",
+		},
+		"selected headers are included": {
+			render: func(c *gin.Context) {
+				c.Header("X-Other", "ignored")
+				c.Header("X-Request-Id", "5")
+				c.String(http.StatusCreated, "created")
+			},
+			options: []GoldenOption{GoldenHeaders("content-type", "X-Request-Id")},
+
+			expected: "HTTP 201\nContent-Type: text/plain; charset=utf-8\nX-Request-Id: 5\n\ncreated\n",
+		},
+		"no content has no body": {
+			render: func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			},
+
+			expected: "HTTP 204\n",
+		},
+		"paths are redacted": {
+			render: func(c *gin.Context) {
+				c.Data(http.StatusOK, binding.MIMEJSON, []byte(`{"at": 1, "items": [{"id": 1, "n": 2}, {"id": 2}], "my key": 3}`))
+			},
+			options: []GoldenOption{RedactPath("$.at", "$.items[*].id", `$["my key"]`, "$.unknown")},
+
+			expected: "HTTP 200\n\n{\n  \"at\": \"<redacted>\",\n  \"items\": [\n    {\n      \"id\": \"<redacted>\",\n" +
+				"      \"n\": 2\n    },\n    {\n      \"id\": \"<redacted>\"\n    }\n  ],\n  \"my key\": \"<redacted>\"\n}\n",
+		},
+		"patterns are redacted": {
+			render: func(c *gin.Context) {
+				c.Header("X-Trace-Id", "123e4567-e89b-12d3-a456-426614174000")
+				c.String(http.StatusOK, "id 123e4567-e89b-12d3-a456-426614174000")
+			},
+			options: []GoldenOption{
+				GoldenHeaders("X-Trace-Id"),
+				RedactPattern(regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)),
+			},
+
+			expected: "HTTP 200\nX-Trace-Id: <redacted>\n\nid <redacted>\n",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			context, writer := PrepareRequest(t)
+			testData.render(context)
+			context.Writer.WriteHeaderNow()
+
+			config := &goldenConfig{}
+			for _, option := range testData.options {
+				option(config)
+			}
+
+			// Act
+			result, err := renderGolden(writer.Result(), writer.Body.Bytes(), config)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestParseJsonPath_ReturnsSegments(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		path string
+
+		expected      []string
+		expectedError string
+	}{
+		"root": {
+			path: "$",
+		},
+		"nested": {
+			path:     `$.items[*].id[0]["a.b"]`,
+			expected: []string{"items", "*", "id", "0", "a.b"},
+		},
+		"missing root": {
+			path:          "items",
+			expectedError: "json path items must start with $",
+		},
+		"invalid segment": {
+			path:          "$.items[a]",
+			expectedError: "invalid json path $.items[a] at '[a]'",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := parseJsonPath(testData.path)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+
+			if testData.expectedError != "" {
+				assert.EqualError(t, err, testData.expectedError)
+			}
+		})
+	}
+}
+
+func TestAssertGolden_ComparesWithGoldenFile(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		golden string
+
+		expectedOk     bool
+		expectedErrorf string
+	}{
+		"matching": {
+			golden:     "HTTP 200\n\n{\n  \"name\": \"gopher\"\n}\n",
+			expectedOk: true,
+		},
+		"different": {
+			golden:         "HTTP 200\n\n{\n  \"name\": \"other\"\n}\n",
+			expectedErrorf: "-  \"name\": \"other\"\n+  \"name\": \"gopher\"\n",
+		},
+		"missing": {
+			expectedErrorf: "does not exist, set GINTESTUTIL_UPDATE_GOLDEN=1 to create it",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			dir := t.TempDir()
+			if testData.golden != "" {
+				_ = os.MkdirAll(filepath.Join(dir, "users"), 0o755)
+				_ = os.WriteFile(filepath.Join(dir, "users", "get.golden"), []byte(testData.golden), 0o600)
+			}
+
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			context.JSON(http.StatusOK, testStruct{Name: "gopher"})
+
+			// Act
+			ok := AssertGolden(testingObject, "users/get", writer.Result(), GoldenDir(dir))
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+
+			if testData.expectedErrorf == "" {
+				assert.Empty(t, testingObject.ErrorfCalls)
+
+				return
+			}
+
+			if assert.Len(t, testingObject.ErrorfCalls, 1) {
+				assert.Contains(t, testingObject.ErrorfCalls[0], testData.expectedErrorf)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Environment variables can't be set in parallel tests
+func TestAssertGolden_UpdatesGoldenFile(t *testing.T) {
+	// Arrange
+	t.Setenv(UpdateGoldenEnv, "true")
+
+	dir := t.TempDir()
+	testingObject := new(mockT)
+	context, writer := PrepareRequest(t)
+	context.JSON(http.StatusOK, testStruct{Name: "gopher"})
+
+	// Act
+	ok := AssertGolden(testingObject, "nested/users/get", writer.Result(), GoldenDir(dir))
+
+	// Assert
+	assert.True(t, ok)
+	assert.Empty(t, testingObject.ErrorfCalls)
+
+	data, err := os.ReadFile(filepath.Join(dir, "nested", "users", "get.golden"))
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(string(data), "HTTP 200\n"))
+	}
+}
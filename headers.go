@@ -0,0 +1,248 @@
+package gintestutil
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// presentHeaders lists the names of the headers of a response for use in error messages
+func presentHeaders(res *http.Response) string {
+	names := make([]string, 0, len(res.Header))
+	for name := range res.Header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// HeaderEquals checks that the response header has exactly the given values, in the same order
+func HeaderEquals(t TestingT, res *http.Response, key string, expected ...string) bool {
+	t.Helper()
+
+	values := res.Header.Values(key)
+	if len(values) == 0 {
+		t.Errorf("Header %s is missing, present headers: %s", key, presentHeaders(res))
+
+		return false
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Header %s is %q not %q", key, values, expected)
+
+		return false
+	}
+
+	return true
+}
+
+// HeaderContains checks that one of the values of the response header contains the given substring
+func HeaderContains(t TestingT, res *http.Response, key string, substring string) bool {
+	t.Helper()
+
+	values := res.Header.Values(key)
+	if len(values) == 0 {
+		t.Errorf("Header %s is missing, present headers: %s", key, presentHeaders(res))
+
+		return false
+	}
+
+	for _, value := range values {
+		if strings.Contains(value, substring) {
+			return true
+		}
+	}
+
+	t.Errorf("Header %s is %q which does not contain %q", key, values, substring)
+
+	return false
+}
+
+// HeaderMatches checks that one of the values of the response header matches the pattern
+func HeaderMatches(t TestingT, res *http.Response, key string, pattern *regexp.Regexp) bool {
+	t.Helper()
+
+	values := res.Header.Values(key)
+	if len(values) == 0 {
+		t.Errorf("Header %s is missing, present headers: %s", key, presentHeaders(res))
+
+		return false
+	}
+
+	for _, value := range values {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+
+	t.Errorf("Header %s is %q which does not match %s", key, values, pattern)
+
+	return false
+}
+
+// HeaderAbsent checks that the response does not have the header
+func HeaderAbsent(t TestingT, res *http.Response, key string) bool {
+	t.Helper()
+
+	if values := res.Header.Values(key); len(values) > 0 {
+		t.Errorf("Header %s should be absent but is %q", key, values)
+
+		return false
+	}
+
+	return true
+}
+
+// CookieOption describes an expected property of a cookie, it returns a description of the mismatch or an
+// empty string if the cookie matches
+type CookieOption func(cookie *http.Cookie) string
+
+// CookieValue expects the cookie to have the given value
+func CookieValue(value string) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.Value == value {
+			return ""
+		}
+
+		return fmt.Sprintf("value is %q not %q", cookie.Value, value)
+	}
+}
+
+// CookiePath expects the cookie to have the given path
+func CookiePath(path string) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.Path == path {
+			return ""
+		}
+
+		return fmt.Sprintf("path is %q not %q", cookie.Path, path)
+	}
+}
+
+// CookieDomain expects the cookie to have the given domain
+func CookieDomain(domain string) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.Domain == domain {
+			return ""
+		}
+
+		return fmt.Sprintf("domain is %q not %q", cookie.Domain, domain)
+	}
+}
+
+// CookieExpires expects the cookie to expire at the given time, compared to the second since that's the precision
+// of the Expires attribute
+func CookieExpires(expires time.Time) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.Expires.Truncate(time.Second).Equal(expires.Truncate(time.Second)) {
+			return ""
+		}
+
+		return fmt.Sprintf("expires is %s not %s", cookie.Expires.UTC(), expires.UTC())
+	}
+}
+
+// CookieMaxAge expects the cookie to have the given Max-Age in seconds, note that Go represents Max-Age=0 as -1
+func CookieMaxAge(maxAge int) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.MaxAge == maxAge {
+			return ""
+		}
+
+		return fmt.Sprintf("max age is %d not %d", cookie.MaxAge, maxAge)
+	}
+}
+
+// CookieHttpOnly expects the HttpOnly attribute to be set or not
+func CookieHttpOnly(httpOnly bool) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.HttpOnly == httpOnly {
+			return ""
+		}
+
+		return fmt.Sprintf("http only is %t not %t", cookie.HttpOnly, httpOnly)
+	}
+}
+
+// CookieSecure expects the Secure attribute to be set or not
+func CookieSecure(secure bool) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.Secure == secure {
+			return ""
+		}
+
+		return fmt.Sprintf("secure is %t not %t", cookie.Secure, secure)
+	}
+}
+
+// CookieSameSite expects the cookie to have the given SameSite mode
+func CookieSameSite(sameSite http.SameSite) CookieOption {
+	return func(cookie *http.Cookie) string {
+		if cookie.SameSite == sameSite {
+			return ""
+		}
+
+		return fmt.Sprintf("same site is %s not %s", formatSameSite(cookie.SameSite), formatSameSite(sameSite))
+	}
+}
+
+// formatSameSite returns the attribute value of a SameSite mode
+func formatSameSite(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteDefaultMode:
+		return "default"
+
+	case http.SameSiteLaxMode:
+		return "Lax"
+
+	case http.SameSiteStrictMode:
+		return "Strict"
+
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+
+	return "unset"
+}
+
+// ResponseCookie checks that the response sets the cookie and that it matches all options, every mismatch is
+// reported. The cookie is returned for further assertions.
+func ResponseCookie(t TestingT, res *http.Response, name string, options ...CookieOption) (*http.Cookie, bool) {
+	t.Helper()
+
+	cookies := res.Cookies()
+
+	var cookie *http.Cookie
+	names := make([]string, 0, len(cookies))
+
+	for _, candidate := range cookies {
+		names = append(names, candidate.Name)
+
+		if candidate.Name == name {
+			cookie = candidate
+		}
+	}
+
+	if cookie == nil {
+		t.Errorf("Cookie %s is not set, present cookies: [%s]", name, strings.Join(names, ", "))
+
+		return nil, false
+	}
+
+	ok := true
+
+	for _, option := range options {
+		if mismatch := option(cookie); mismatch != "" {
+			t.Errorf("Cookie %s: %s", name, mismatch)
+			ok = false
+		}
+	}
+
+	return cookie, ok
+}
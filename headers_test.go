@@ -0,0 +1,161 @@
+package gintestutil
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderAssertions_ReturnExpectedResult(t *testing.T) {
+	t.Parallel()
+	response := &http.Response{Header: http.Header{
+		"Cache-Control": []string{"no-cache", "no-store"},
+		"Etag":          []string{`"abc"`},
+	}}
+
+	tests := map[string]struct {
+		assertion func(TestingT) bool
+
+		expectedOk     bool
+		expectedErrorf []string
+	}{
+		"equals": {
+			assertion:  func(t TestingT) bool { return HeaderEquals(t, response, "cache-control", "no-cache", "no-store") },
+			expectedOk: true,
+		},
+		"equals different values": {
+			assertion:      func(t TestingT) bool { return HeaderEquals(t, response, "Cache-Control", "no-cache") },
+			expectedErrorf: []string{`Header Cache-Control is ["no-cache" "no-store"] not ["no-cache"]`},
+		},
+		"equals missing header": {
+			assertion:      func(t TestingT) bool { return HeaderEquals(t, response, "Vary", "Origin") },
+			expectedErrorf: []string{"Header Vary is missing, present headers: [Cache-Control, Etag]"},
+		},
+		"contains": {
+			assertion:  func(t TestingT) bool { return HeaderContains(t, response, "Cache-Control", "store") },
+			expectedOk: true,
+		},
+		"contains different values": {
+			assertion:      func(t TestingT) bool { return HeaderContains(t, response, "Etag", "def") },
+			expectedErrorf: []string{`Header Etag is ["\"abc\""] which does not contain "def"`},
+		},
+		"contains missing header": {
+			assertion:      func(t TestingT) bool { return HeaderContains(t, response, "Vary", "Origin") },
+			expectedErrorf: []string{"Header Vary is missing, present headers: [Cache-Control, Etag]"},
+		},
+		"matches": {
+			assertion:  func(t TestingT) bool { return HeaderMatches(t, response, "Etag", regexp.MustCompile(`^"[a-z]+"$`)) },
+			expectedOk: true,
+		},
+		"matches different values": {
+			assertion:      func(t TestingT) bool { return HeaderMatches(t, response, "Etag", regexp.MustCompile(`^W/`)) },
+			expectedErrorf: []string{`Header Etag is ["\"abc\""] which does not match ^W/`},
+		},
+		"absent": {
+			assertion:  func(t TestingT) bool { return HeaderAbsent(t, response, "Vary") },
+			expectedOk: true,
+		},
+		"absent present header": {
+			assertion:      func(t TestingT) bool { return HeaderAbsent(t, response, "Etag") },
+			expectedErrorf: []string{`Header Etag should be absent but is ["\"abc\""]`},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+
+			// Act
+			ok := testData.assertion(testingObject)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestResponseCookie_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		name    string
+		options []CookieOption
+
+		expectedOk     bool
+		expectedErrorf []string
+	}{
+		"all properties match": {
+			name: "session",
+			options: []CookieOption{
+				CookieValue("abc"),
+				CookiePath("/api"),
+				CookieDomain("example.com"),
+				CookieExpires(expires),
+				CookieMaxAge(3600),
+				CookieHttpOnly(true),
+				CookieSecure(true),
+				CookieSameSite(http.SameSiteStrictMode),
+			},
+			expectedOk: true,
+		},
+		"all properties differ": {
+			name: "session",
+			options: []CookieOption{
+				CookieValue("def"),
+				CookiePath("/"),
+				CookieDomain("other.com"),
+				CookieExpires(expires.Add(time.Hour)),
+				CookieMaxAge(60),
+				CookieHttpOnly(false),
+				CookieSecure(false),
+				CookieSameSite(http.SameSiteLaxMode),
+			},
+			expectedErrorf: []string{
+				`Cookie session: value is "abc" not "def"`,
+				`Cookie session: path is "/api" not "/"`,
+				`Cookie session: domain is "example.com" not "other.com"`,
+				"Cookie session: expires is 2030-01-02 03:04:05 +0000 UTC not 2030-01-02 04:04:05 +0000 UTC",
+				"Cookie session: max age is 3600 not 60",
+				"Cookie session: http only is true not false",
+				"Cookie session: secure is true not false",
+				"Cookie session: same site is Strict not Lax",
+			},
+		},
+		"missing cookie": {
+			name:           "other",
+			expectedErrorf: []string{"Cookie other is not set, present cookies: [session, theme]"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingObject := new(mockT)
+			context, writer := PrepareRequest(t)
+			context.SetSameSite(http.SameSiteStrictMode)
+			context.SetCookie("session", "abc", 3600, "/api", "example.com", true, true)
+			context.SetCookie("theme", "dark", 0, "/", "", false, false)
+
+			response := writer.Result()
+			response.Header["Set-Cookie"][0] += "; Expires=" + expires.Format(http.TimeFormat)
+
+			// Act
+			cookie, ok := ResponseCookie(testingObject, response, testData.name, testData.options...)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expectedErrorf, testingObject.ErrorfCalls)
+			assert.Equal(t, testData.name == "session", cookie != nil)
+		})
+	}
+}
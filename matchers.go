@@ -0,0 +1,151 @@
+package gintestutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestMatcher decides whether a call to an endpoint counts towards an expectation, see Matching
+type RequestMatcher func(c *gin.Context) bool
+
+// MatchQuery matches calls where the query parameter has exactly the given values
+func MatchQuery(key string, values ...string) RequestMatcher {
+	return func(c *gin.Context) bool {
+		actual, _ := c.GetQueryArray(key)
+
+		return reflect.DeepEqual(actual, values)
+	}
+}
+
+// MatchHeader matches calls where one of the values of the header equals the given value
+func MatchHeader(key string, value string) RequestMatcher {
+	return func(c *gin.Context) bool {
+		for _, actual := range c.Request.Header.Values(key) {
+			if actual == value {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchUrlParam matches calls where the url parameter of the route equals the given value
+func MatchUrlParam(key string, value string) RequestMatcher {
+	return func(c *gin.Context) bool {
+		actual, ok := c.Params.Get(key)
+
+		return ok && actual == value
+	}
+}
+
+// MatchJsonField matches calls with a json body where the field at the path equals the given value, which is compared
+// after converting it to json. Paths look like $.name, $.items[0].id or $["trace-id"], wildcards are not supported.
+// Invalid paths and values are reported immediately, the resulting matcher never matches.
+func MatchJsonField(t TestingT, path string, value any) RequestMatcher {
+	t.Helper()
+
+	never := func(*gin.Context) bool { return false }
+
+	segments, err := parseJsonPath(path)
+	if err != nil {
+		t.Errorf("%v", err)
+
+		return never
+	}
+
+	for _, segment := range segments {
+		if segment == "*" {
+			t.Errorf("json path %s can't contain wildcards in MatchJsonField", path)
+
+			return never
+		}
+	}
+
+	expectedData, err := json.Marshal(value)
+	if err != nil {
+		t.Errorf("Failed to marshal expected value '%T': %v", value, err)
+
+		return never
+	}
+
+	expected, err := decodeJsonValue(expectedData)
+	if err != nil {
+		t.Errorf("Failed to unmarshall expected value '%s': %v", truncateBody(expectedData), err)
+
+		return never
+	}
+
+	return func(c *gin.Context) bool {
+		body, err := decodeJsonValue(requestBody(c))
+		if err != nil {
+			return false
+		}
+
+		actual, ok := lookupJsonPath(body, segments)
+
+		return ok && len(diffJson("$", expected, actual)) == 0
+	}
+}
+
+// MatchRequest matches calls for which the function returns true, the body of the request can be read safely
+func MatchRequest(matcher func(*http.Request) bool) RequestMatcher {
+	return func(c *gin.Context) bool {
+		if c.Request.Body == nil {
+			return matcher(c.Request)
+		}
+
+		data := requestBody(c)
+		defer func() {
+			c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		}()
+
+		return matcher(c.Request)
+	}
+}
+
+// requestBody reads the body of the request and replaces it, so it can still be read by the handler
+func requestBody(c *gin.Context) []byte {
+	if c.Request.Body == nil {
+		return nil
+	}
+
+	data, _ := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	return data
+}
+
+// lookupJsonPath returns the value at the path in a decoded json value
+func lookupJsonPath(value any, segments []string) (any, bool) {
+	for _, segment := range segments {
+		switch typedValue := value.(type) {
+		case map[string]any:
+			item, ok := typedValue[segment]
+			if !ok {
+				return nil, false
+			}
+
+			value = item
+
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typedValue) {
+				return nil, false
+			}
+
+			value = typedValue[index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,197 @@
+package gintestutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMatchers_ReturnExpectedResult(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		matcher RequestMatcher
+
+		expected bool
+	}{
+		"query matches": {
+			matcher:  MatchQuery("tags", "a", "b"),
+			expected: true,
+		},
+		"query differs": {
+			matcher: MatchQuery("tags", "a"),
+		},
+		"query missing": {
+			matcher: MatchQuery("other", "a"),
+		},
+		"header matches": {
+			matcher:  MatchHeader("x-tenant", "ing"),
+			expected: true,
+		},
+		"header differs": {
+			matcher: MatchHeader("X-Tenant", "other"),
+		},
+		"url param matches": {
+			matcher:  MatchUrlParam("id", "5"),
+			expected: true,
+		},
+		"url param differs": {
+			matcher: MatchUrlParam("id", "6"),
+		},
+		"url param missing": {
+			matcher: MatchUrlParam("other", ""),
+		},
+		"json field matches": {
+			matcher:  MatchJsonField(t, "$.items[1].price", 10),
+			expected: true,
+		},
+		"json object matches": {
+			matcher:  MatchJsonField(t, "$.items[0]", map[string]any{"price": 5.0}),
+			expected: true,
+		},
+		"json field differs": {
+			matcher: MatchJsonField(t, "$.items[1].price", 10.5),
+		},
+		"json field missing": {
+			matcher: MatchJsonField(t, "$.items[2].price", 10),
+		},
+		"request function matches": {
+			matcher: MatchRequest(func(request *http.Request) bool {
+				body, _ := io.ReadAll(request.Body)
+
+				return request.Method == http.MethodPost && len(body) > 0
+			}),
+			expected: true,
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			context, _ := PrepareRequest(t,
+				WithMethod(http.MethodPost),
+				WithRoute("/users/:id", map[string]any{"id": "5"}),
+				WithQueryParams(map[string]any{"tags": []string{"a", "b"}}),
+				WithHeaders(http.Header{"X-Tenant": []string{"ing"}}),
+				WithJsonBody(t, map[string]any{"items": []any{map[string]any{"price": 5}, map[string]any{"price": 10.0}}}),
+			)
+
+			// Act
+			result := testData.matcher(context)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+
+			body, _ := io.ReadAll(context.Request.Body)
+			assert.Equal(t, `{"items":[{"price":5},{"price":10}]}`, string(body))
+		})
+	}
+}
+
+func TestMatchJsonField_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		path  string
+		value any
+
+		expectedErrorf string
+	}{
+		"missing root": {
+			path:           "items.sku",
+			value:          "A-1",
+			expectedErrorf: "json path items.sku must start with $",
+		},
+		"invalid path": {
+			path:           "$.items[x]",
+			value:          "A-1",
+			expectedErrorf: "invalid json path $.items[x] at '[x]'",
+		},
+		"wildcard": {
+			path:           "$.items[*].sku",
+			value:          "A-1",
+			expectedErrorf: "json path $.items[*].sku can't contain wildcards in MatchJsonField",
+		},
+		"unsupported value": {
+			path:           "$.id",
+			value:          make(chan int),
+			expectedErrorf: "Failed to marshal expected value 'chan int': json: unsupported type: chan int",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			context, _ := PrepareRequest(t, WithJsonBody(t, map[string]any{"id": 5, "items": []any{map[string]any{"sku": "A-1"}}}))
+
+			// Act
+			matcher := MatchJsonField(testObject, testData.path, testData.value)
+
+			// Assert
+			assert.Equal(t, []string{testData.expectedErrorf}, testObject.ErrorfCalls)
+			assert.False(t, matcher(context))
+		})
+	}
+}
+
+func TestExpectCalled_OnlyCountsMatchingCalls(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		reportUnmatched bool
+
+		expectedErrorf []string
+	}{
+		"ignore unmatched": {},
+		"report unmatched": {
+			reportUnmatched: true,
+			expectedErrorf:  []string{"POST /orders received a call that does not match the expectation: /orders?priority=low\n"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			ginContext := gin.New()
+
+			var bodies []string
+			ginContext.POST("/orders", func(ctx *gin.Context) {
+				body, _ := ctx.GetRawData()
+				bodies = append(bodies, string(body))
+			})
+
+			options := []ExpectOption{Matching(MatchQuery("priority", "high"), MatchJsonField(t, "$.id", 5))}
+			if testData.reportUnmatched {
+				options = append(options, ReportUnmatched())
+			}
+
+			expectation := ExpectCalled(testObject, ginContext, http.MethodPost, "/orders", options...)
+
+			// Act
+			for _, priority := range []string{"low", "high"} {
+				context, _ := PrepareRequest(t,
+					WithMethod(http.MethodPost),
+					WithUrl("/orders"),
+					WithQueryParams(map[string]any{"priority": priority}),
+					WithJsonBody(t, map[string]any{"id": 5}),
+				)
+
+				ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+			}
+
+			// Assert
+			assert.True(t, EnsureCompletion(new(testing.T), expectation, WithTimeout(expectTimeout)))
+			assert.Equal(t, testData.expectedErrorf, testObject.ErrorfCalls)
+			assert.Equal(t, []string{`{"id":5}`, `{"id":5}`}, bodies)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package gintestutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MiddlewareResult describes what a middleware did with a request in RunMiddleware
+type MiddlewareResult struct {
+	// Continued is true if the middleware passed the request on to the next handler
+	Continued bool
+
+	// Aborted is true if the middleware aborted the chain
+	Aborted bool
+
+	// Status is the status code of the response, such as the status given to AbortWithStatus
+	Status int
+
+	// Header contains the headers that were written to the response
+	Header http.Header
+
+	// Keys contains the keys that were set with c.Set
+	Keys map[string]any
+
+	// Errors contains the errors that were added with c.Error
+	Errors []*gin.Error
+
+	// Context and Recorder can be used for further assertions
+	Context  *gin.Context
+	Recorder *httptest.ResponseRecorder
+}
+
+// RunMiddleware runs the middleware with the request made with the request options and reports what it did. The
+// middleware is followed by a handler that only records that it was reached. The middleware runs on the route of
+// WithRoute if given, or on the path of the url otherwise, so c.FullPath and c.Param work as expected. The keys and
+// errors of WithContextKeys and WithContextErrors are set before the middleware runs.
+func RunMiddleware(t TestingT, middleware gin.HandlerFunc, options ...RequestOption) MiddlewareResult {
+	t.Helper()
+
+	config := newRequestConfig(options)
+
+	route := config.route
+	if route == "" {
+		request, ok := newRequest(t, config)
+		if !ok {
+			return MiddlewareResult{}
+		}
+
+		route = rootPath(request.URL.Path)
+	}
+
+	result := MiddlewareResult{}
+
+	// The engine resets the abort state of the context once the request is handled, so it's recorded in the chain
+	observer := func(c *gin.Context) {
+		seedContext(t, c, config)
+		c.Next()
+		result.Aborted = c.IsAborted()
+	}
+
+	sentinel := func(*gin.Context) {
+		result.Continued = true
+	}
+
+	engine := gin.New()
+	if !registerRoute(t, engine, config.method, route, observer, middleware, sentinel) {
+		return result
+	}
+
+	context, recorder := Serve(t, engine, options...)
+
+	result.Status = recorder.Code
+	result.Header = recorder.Header()
+	result.Keys = context.Keys
+	result.Errors = context.Errors
+	result.Context = context
+	result.Recorder = recorder
+
+	return result
+}
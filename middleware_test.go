@@ -0,0 +1,119 @@
+package gintestutil
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMiddleware_ReportsWhatMiddlewareDid(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		middleware gin.HandlerFunc
+		options    []RequestOption
+
+		expectedContinued bool
+		expectedAborted   bool
+		expectedStatus    int
+		expectedHeader    string
+		expectedKeys      map[string]any
+		expectedErrors    []string
+	}{
+		"continues": {
+			middleware: func(c *gin.Context) {
+				c.Set("user", "John")
+				c.Header("X-User", "John")
+			},
+			expectedContinued: true,
+			expectedStatus:    http.StatusOK,
+			expectedHeader:    "John",
+			expectedKeys:      map[string]any{"user": "John"},
+		},
+		"aborts": {
+			middleware: func(c *gin.Context) {
+				c.AbortWithStatus(http.StatusUnauthorized)
+			},
+			expectedAborted: true,
+			expectedStatus:  http.StatusUnauthorized,
+		},
+		"aborts with error": {
+			middleware: func(c *gin.Context) {
+				_ = c.AbortWithError(http.StatusForbidden, errors.New("forbidden"))
+			},
+			expectedAborted: true,
+			expectedStatus:  http.StatusForbidden,
+			expectedErrors:  []string{"forbidden"},
+		},
+		"uses route": {
+			middleware: func(c *gin.Context) {
+				c.Set("route", c.FullPath())
+				c.Set("id", c.Param("id"))
+				c.Next()
+			},
+			options: []RequestOption{
+				WithMethod(http.MethodDelete),
+				WithRoute("/users/:id", map[string]any{"id": "5"}),
+			},
+			expectedContinued: true,
+			expectedStatus:    http.StatusOK,
+			expectedKeys:      map[string]any{"route": "/users/:id", "id": "5"},
+		},
+		"uses path of url": {
+			middleware: func(c *gin.Context) {
+				c.Set("route", c.FullPath())
+			},
+			options:           []RequestOption{WithUrl("https://example.com/health")},
+			expectedContinued: true,
+			expectedStatus:    http.StatusOK,
+			expectedKeys:      map[string]any{"route": "/health"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+
+			// Act
+			result := RunMiddleware(testObject, testData.middleware, testData.options...)
+
+			// Assert
+			assert.Empty(t, testObject.ErrorfCalls)
+			assert.Equal(t, testData.expectedContinued, result.Continued)
+			assert.Equal(t, testData.expectedAborted, result.Aborted)
+			assert.Equal(t, testData.expectedStatus, result.Status)
+			assert.Equal(t, testData.expectedHeader, result.Header.Get("X-User"))
+			assert.Equal(t, testData.expectedKeys, result.Keys)
+			assert.Equal(t, testData.expectedErrors, gin.Context{Errors: result.Errors}.Errors.Errors())
+		})
+	}
+}
+
+func TestRunMiddleware_SeedsContextState(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	var tenant any
+	var errorCount int
+	middleware := func(c *gin.Context) {
+		tenant = c.MustGet("tenant")
+		errorCount = len(c.Errors)
+	}
+
+	// Act
+	result := RunMiddleware(testObject, middleware,
+		WithContextKeys(map[string]any{"tenant": "ing"}),
+		WithContextErrors(errors.New("earlier")),
+	)
+
+	// Assert
+	assert.True(t, result.Continued)
+	assert.Equal(t, "ing", tenant)
+	assert.Equal(t, 1, errorCount)
+}
@@ -0,0 +1,100 @@
+package gintestutil
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultFileContentType is used for files without an explicit or detectable content type
+const defaultFileContentType = "application/octet-stream"
+
+// quoteEscaper escapes values used in a Content-Disposition header, identical to the one in mime/multipart
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// MultipartPart is a single field or file of a multipart body, used in WithMultipartBody
+type MultipartPart func(*multipart.Writer) error
+
+// MultipartField adds a regular form field to the multipart body, multiple values result in multiple parts
+func MultipartField(name string, values ...string) MultipartPart {
+	return func(writer *multipart.Writer) error {
+		for _, value := range values {
+			if err := writer.WriteField(name, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// MultipartFile adds an in-memory file to the multipart body. If no content type is given,
+// application/octet-stream is used.
+func MultipartFile(field string, filename string, contentType string, data []byte) MultipartPart {
+	return func(writer *multipart.Writer) error {
+		if contentType == "" {
+			contentType = defaultFileContentType
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(field), quoteEscaper.Replace(filename)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = part.Write(data)
+
+		return err
+	}
+}
+
+// MultipartFileFromDisk adds a file from disk to the multipart body, the filename is the base name of the path. If no
+// content type is given, it is derived from the extension of the file.
+func MultipartFileFromDisk(field string, path string, contentType string) MultipartPart {
+	return func(writer *multipart.Writer) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(path))
+		}
+
+		return MultipartFile(field, filepath.Base(path), contentType, data)(writer)
+	}
+}
+
+// WithMultipartBody specifies a multipart/form-data body consisting of the given fields and files and sets the
+// Content-Type with the generated boundary, unless one was given using WithHeaders. Will report an error if a part
+// could not be written, for example when a file does not exist.
+func WithMultipartBody(t TestingT, parts ...MultipartPart) RequestOption {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	for _, part := range parts {
+		if err := part(writer); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Error(err)
+	}
+
+	return func(config *requestConfig) {
+		config.body = body.Bytes()
+		config.contentType = writer.FormDataContentType()
+	}
+}
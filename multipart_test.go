@@ -0,0 +1,71 @@
+package gintestutil
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMultipartBody_CanBeReadByGin(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	path := filepath.Join(t.TempDir(), "report.json")
+	_ = os.WriteFile(path, []byte(`{"a": "b"}`), 0o600)
+
+	mockT := new(mockT)
+
+	context, _ := PrepareRequest(mockT,
+		WithMethod(http.MethodPost),
+		WithMultipartBody(mockT,
+			MultipartField("name", "gopher"),
+			MultipartField("tags", "a", "b"),
+			MultipartFile("avatar", `my "avatar".png`, "image/png", []byte("not really a png")),
+			MultipartFileFromDisk("report", path, ""),
+		),
+	)
+
+	// Act
+	avatar, avatarErr := context.FormFile("avatar")
+	report, reportErr := context.FormFile("report")
+
+	// Assert
+	assert.Empty(t, mockT.ErrorCalls)
+	assert.True(t, strings.HasPrefix(context.ContentType(), "multipart/form-data"))
+
+	assert.Equal(t, "gopher", context.PostForm("name"))
+	assert.Equal(t, []string{"a", "b"}, context.PostFormArray("tags"))
+
+	if assert.NoError(t, avatarErr) {
+		assert.Equal(t, `my "avatar".png`, avatar.Filename)
+		assert.Equal(t, "image/png", avatar.Header.Get("Content-Type"))
+		assert.Equal(t, int64(16), avatar.Size)
+	}
+
+	if assert.NoError(t, reportErr) {
+		assert.Equal(t, "report.json", report.Filename)
+		assert.Equal(t, "application/json", report.Header.Get("Content-Type"))
+
+		file, _ := report.Open()
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, `{"a": "b"}`, string(data))
+	}
+}
+
+func TestWithMultipartBody_ReportsMissingFile(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	mockT := new(mockT)
+
+	// Act
+	_ = WithMultipartBody(mockT, MultipartFileFromDisk("report", filepath.Join(t.TempDir(), "missing.json"), ""))
+
+	// Assert
+	if assert.Len(t, mockT.ErrorCalls, 1) {
+		assert.ErrorIs(t, mockT.ErrorCalls[0].(error), os.ErrNotExist)
+	}
+}
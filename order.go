@@ -0,0 +1,80 @@
+package gintestutil
+
+import (
+	"sort"
+	"strings"
+)
+
+// orderedCall is a call together with the expectation that recorded it
+type orderedCall struct {
+	expectation *CallExpectation
+	call        Call
+}
+
+// InOrder asserts that all calls of each expectation were received before any call of the next expectation, like
+// a flow that has to call /reserve, then /charge and then /confirm. Every expectation must have been called,
+// so it's best used after EnsureCompletion. On failure the observed sequence of calls is reported.
+func InOrder(t TestingT, expectations ...*CallExpectation) bool {
+	t.Helper()
+
+	var observed []orderedCall
+
+	for _, expectation := range expectations {
+		if expectation == nil {
+			t.Error("expectation cannot be nil")
+
+			return false
+		}
+
+		calls := expectation.Calls()
+		if len(calls) == 0 {
+			t.Errorf("%s was never called, so the order can't be verified", expectation)
+
+			return false
+		}
+
+		for _, call := range calls {
+			observed = append(observed, orderedCall{expectation: expectation, call: call})
+		}
+	}
+
+	sort.Slice(observed, func(i, j int) bool {
+		return observed[i].call.Sequence < observed[j].call.Sequence
+	})
+
+	// Walking through the calls in the order they were received, the expectation may only move forward
+	position := 0
+	for _, current := range observed {
+		for position < len(expectations) && expectations[position] != current.expectation {
+			position++
+		}
+
+		if position == len(expectations) {
+			t.Errorf("calls were not made in order, expected: %s, observed: %s", describeExpectations(expectations), describeCalls(observed))
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeExpectations returns the expectations as a comma separated list
+func describeExpectations(expectations []*CallExpectation) string {
+	descriptions := make([]string, len(expectations))
+	for index, expectation := range expectations {
+		descriptions[index] = expectation.String()
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// describeCalls returns the routes of the calls as a comma separated list
+func describeCalls(calls []orderedCall) string {
+	descriptions := make([]string, len(calls))
+	for index, call := range calls {
+		descriptions[index] = call.expectation.String()
+	}
+
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,89 @@
+package gintestutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInOrder_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		calls []string
+
+		expected       bool
+		expectedErrorf []string
+	}{
+		"in order": {
+			calls:    []string{"/reserve", "/charge", "/confirm"},
+			expected: true,
+		},
+		"repeated calls in order": {
+			calls:    []string{"/reserve", "/reserve", "/charge", "/confirm", "/confirm"},
+			expected: true,
+		},
+		"out of order": {
+			calls: []string{"/reserve", "/confirm", "/charge"},
+			expectedErrorf: []string{
+				"calls were not made in order, expected: POST /reserve, POST /charge, POST /confirm, observed: POST /reserve, POST /confirm, POST /charge",
+			},
+		},
+		"repeated call after next step": {
+			calls: []string{"/reserve", "/charge", "/reserve", "/confirm"},
+			expectedErrorf: []string{
+				"calls were not made in order, expected: POST /reserve, POST /charge, POST /confirm, observed: POST /reserve, POST /charge, POST /reserve, POST /confirm",
+			},
+		},
+		"never called": {
+			calls:          []string{"/reserve", "/confirm"},
+			expectedErrorf: []string{"POST /charge was never called, so the order can't be verified"},
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testObject := new(mockT)
+			ginContext := gin.New()
+
+			var expectations []*CallExpectation
+			for _, path := range []string{"/reserve", "/charge", "/confirm"} {
+				expectations = append(expectations, ExpectCalled(testObject, ginContext, http.MethodPost, path, AtLeast(0)))
+
+				ginContext.POST(path, func(ctx *gin.Context) {
+					ctx.Status(http.StatusOK)
+				})
+			}
+
+			for _, path := range testData.calls {
+				context, _ := PrepareRequest(t, WithMethod(http.MethodPost), WithUrl(path))
+				ginContext.ServeHTTP(httptest.NewRecorder(), context.Request)
+			}
+
+			// Act
+			result := InOrder(testObject, expectations...)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+			assert.Equal(t, testData.expectedErrorf, testObject.ErrorfCalls)
+		})
+	}
+}
+
+func TestInOrder_FailsOnNilExpectation(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	testObject := new(mockT)
+
+	// Act
+	result := InOrder(testObject, nil)
+
+	// Assert
+	assert.False(t, result)
+	assert.Equal(t, []any{"expectation cannot be nil"}, testObject.ErrorCalls)
+}
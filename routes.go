@@ -0,0 +1,148 @@
+package gintestutil
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/gin-gonic/gin"
+)
+
+// joinRoute joins a relative route to the base path of a group, keeping a trailing slash like gin does
+func joinRoute(basePath string, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+
+	result := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(result, "/") {
+		return result + "/"
+	}
+
+	return result
+}
+
+// unexportedField makes an unexported struct field accessible, gin does not expose the engine of a group
+// or the handlers of registered routes
+func unexportedField(value reflect.Value, name string) (reflect.Value, error) {
+	field := value.FieldByName(name)
+	if !field.IsValid() {
+		return reflect.Value{}, fmt.Errorf("%s has no field %s, this version of gin is not supported", value.Type(), name)
+	}
+
+	//nolint:gosec // There is no other way to reach these fields
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem(), nil
+}
+
+// resolveRouter returns the engine of an engine or router group, along with the absolute path of the route
+func resolveRouter(router gin.IRoutes, relativePath string) (*gin.Engine, string, error) {
+	switch typedRouter := router.(type) {
+	case *gin.Engine:
+		if typedRouter == nil {
+			return nil, "", errors.New("engine cannot be nil")
+		}
+
+		return typedRouter, joinRoute(typedRouter.BasePath(), relativePath), nil
+
+	case *gin.RouterGroup:
+		if typedRouter == nil {
+			return nil, "", errors.New("router group cannot be nil")
+		}
+
+		engineField, err := unexportedField(reflect.ValueOf(typedRouter).Elem(), "engine")
+		if err != nil {
+			return nil, "", err
+		}
+
+		engine, _ := engineField.Interface().(*gin.Engine)
+
+		return engine, joinRoute(typedRouter.BasePath(), relativePath), nil
+
+	case nil:
+		return nil, "", errors.New("engine cannot be nil")
+	}
+
+	return nil, "", fmt.Errorf("%T is not supported, use a *gin.Engine or *gin.RouterGroup", router)
+}
+
+// routeRegistered checks whether a route exists on the engine
+func routeRegistered(engine *gin.Engine, method string, path string) bool {
+	for _, route := range engine.Routes() {
+		if route.Method == method && route.Path == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registeredRoutes lists the routes of the engine for use in error messages
+func registeredRoutes(engine *gin.Engine) string {
+	routes := engine.Routes()
+
+	result := make([]string, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, route.Method+" "+route.Path)
+	}
+
+	sort.Strings(result)
+
+	return "[" + strings.Join(result, ", ") + "]"
+}
+
+// prependToRoute adds the middleware in front of the handlers of a route that was already registered. Middleware
+// added with Use only applies to routes registered afterwards, so the handler tree of the engine is modified instead.
+func prependToRoute(engine *gin.Engine, method string, path string, middleware gin.HandlerFunc) error {
+	trees, err := unexportedField(reflect.ValueOf(engine).Elem(), "trees")
+	if err != nil {
+		return err
+	}
+
+	for index := 0; index < trees.Len(); index++ {
+		tree := trees.Index(index)
+		if tree.FieldByName("method").String() != method {
+			continue
+		}
+
+		found, err := prependToNode(tree.FieldByName("root"), path, middleware)
+		if err != nil || found {
+			return err
+		}
+	}
+
+	return fmt.Errorf("route %s %s is not registered", method, path)
+}
+
+// prependToNode searches the node and its children for the route and prepends the middleware to its handlers
+func prependToNode(node reflect.Value, path string, middleware gin.HandlerFunc) (bool, error) {
+	if node.IsNil() {
+		return false, nil
+	}
+
+	node = node.Elem()
+
+	handlers, err := unexportedField(node, "handlers")
+	if err != nil {
+		return false, err
+	}
+
+	if handlers.Len() > 0 && node.FieldByName("fullPath").String() == path {
+		existing, _ := handlers.Interface().(gin.HandlersChain)
+		handlers.Set(reflect.ValueOf(append(gin.HandlersChain{middleware}, existing...)))
+
+		return true, nil
+	}
+
+	children := node.FieldByName("children")
+	for index := 0; index < children.Len(); index++ {
+		if found, err := prependToNode(children.Index(index), path, middleware); err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
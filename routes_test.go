@@ -0,0 +1,141 @@
+package gintestutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinRoute_ReturnsAbsolutePath(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		basePath     string
+		relativePath string
+
+		expected string
+	}{
+		"engine": {
+			basePath:     "/",
+			relativePath: "/ping",
+			expected:     "/ping",
+		},
+		"group": {
+			basePath:     "/api/v1",
+			relativePath: "/users/:id",
+			expected:     "/api/v1/users/:id",
+		},
+		"trailing slash": {
+			basePath:     "/api",
+			relativePath: "users/",
+			expected:     "/api/users/",
+		},
+		"empty": {
+			basePath: "/api",
+			expected: "/api",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := joinRoute(testData.basePath, testData.relativePath)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestResolveRouter_ReturnsEngineAndPath(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	engine := gin.New()
+	group := engine.Group("/api").Group("/v1")
+
+	// Act
+	result, path, err := resolveRouter(group, "/users")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Same(t, engine, result)
+	assert.Equal(t, "/api/v1/users", path)
+}
+
+// unsupportedRouter is an implementation of gin.IRoutes that ExpectCalled can't hook into
+type unsupportedRouter struct {
+	gin.IRoutes
+}
+
+func TestResolveRouter_ReturnsErrorOnInvalidRouter(t *testing.T) {
+	t.Parallel()
+	var nilGroup *gin.RouterGroup
+
+	tests := map[string]struct {
+		router gin.IRoutes
+
+		expectedError string
+	}{
+		"nil": {
+			expectedError: "engine cannot be nil",
+		},
+		"nil group": {
+			router:        nilGroup,
+			expectedError: "router group cannot be nil",
+		},
+		"unsupported": {
+			router:        unsupportedRouter{},
+			expectedError: "gintestutil.unsupportedRouter is not supported, use a *gin.Engine or *gin.RouterGroup",
+		},
+	}
+
+	for name, testData := range tests {
+		testData := testData
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			_, _, err := resolveRouter(testData.router, "/")
+
+			// Assert
+			assert.EqualError(t, err, testData.expectedError)
+		})
+	}
+}
+
+func TestPrependToRoute_RunsMiddlewareBeforeHandlers(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	engine := gin.New()
+
+	var order []string
+	engine.Use(func(c *gin.Context) { order = append(order, "use") })
+	engine.GET("/users/:id", func(c *gin.Context) { order = append(order, "handler") })
+	engine.GET("/users/:id/orders", func(c *gin.Context) { order = append(order, "other") })
+
+	// Act
+	err := prependToRoute(engine, http.MethodGet, "/users/:id", func(c *gin.Context) { order = append(order, "prepended") })
+
+	// Assert
+	assert.NoError(t, err)
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/5", nil))
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/5/orders", nil))
+	assert.Equal(t, []string{"prepended", "use", "handler", "use", "other"}, order)
+}
+
+func TestPrependToRoute_ReturnsErrorOnUnknownRoute(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	engine := gin.New()
+	engine.GET("/users", func(c *gin.Context) {})
+
+	// Act
+	err := prependToRoute(engine, http.MethodPost, "/users", func(c *gin.Context) {})
+
+	// Assert
+	assert.EqualError(t, err, "route POST /users is not registered")
+}
@@ -2,6 +2,7 @@ package gintestutil
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -9,6 +10,8 @@ import (
 var (
 	_ TestingT = new(testing.T)
 	_ TestingT = new(mockT)
+	_ cleanupT = new(testing.T)
+	_ cleanupT = new(mockT)
 )
 
 // TestingT is an interface representing testing.T in our tests, allows for verifying Errorf calls. It's perfectly
@@ -19,10 +22,14 @@ type TestingT interface {
 	Errorf(string, ...any)
 }
 
-// mockT is the mock version of the TestingT interface, used to verify Errorf calls
+// mockT is the mock version of the TestingT interface, used to verify Errorf calls. Calls are guarded by a mutex
+// because servers may report errors from their own goroutines.
 type mockT struct {
+	mutex sync.Mutex
+
 	ErrorCalls  []any
 	ErrorfCalls []string
+	Cleanups    []func()
 }
 
 // Helper does nothing
@@ -30,10 +37,24 @@ func (m *mockT) Helper() {}
 
 // Errorf saves Errorf calls in an error for verification
 func (m *mockT) Errorf(format string, args ...any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.ErrorfCalls = append(m.ErrorfCalls, fmt.Sprintf(format, args...))
 }
 
 // Error saves Error calls in an error for verification
 func (m *mockT) Error(args ...any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.ErrorCalls = append(m.ErrorCalls, args...)
 }
+
+// Cleanup saves Cleanup calls so they can be run by the test
+func (m *mockT) Cleanup(cleanup func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.Cleanups = append(m.Cleanups, cleanup)
+}